@@ -0,0 +1,96 @@
+package geocoder
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AlternateRoute wraps an alternate route of the alternateroutes service
+type AlternateRoute struct {
+	Route Route `json:"route"`
+}
+
+// Routes returns the route followed by its alternate routes (if any).
+func (results DirectionsResults) Routes() []Route {
+	routes := []Route{results.Route}
+	for _, alternate := range results.Route.AlternateRoutes {
+		routes = append(routes, alternate.Route)
+	}
+	return routes
+}
+
+// attributeDistance sums the distance of the maneuvers with an attribute
+func (route Route) attributeDistance(attribute ManeuverAttribute) (distance float64) {
+	for _, leg := range route.Legs {
+		for _, maneuver := range leg.Maneuvers {
+			if maneuver.Attributes.Has(attribute) {
+				distance += maneuver.Distance
+			}
+		}
+	}
+	return
+}
+
+// RouteComparison summarizes how a route differs from a base route.
+// Differences are route minus base route, distances are in the unit of the routes.
+type RouteComparison struct {
+	// Difference in time (seconds)
+	Time int
+	// Difference in distance
+	Distance float64
+	// Difference in fuel used
+	FuelUsed float64
+	// Difference in distance over toll roads
+	TollDistance float64
+	// Difference in distance over highways (limited access)
+	HighwayDistance float64
+	// Whether the route uses toll roads while the base route does not (or vice versa)
+	AddsTollRoad, AvoidsTollRoad bool
+	// Whether the route uses highways while the base route does not (or vice versa)
+	AddsHighway, AvoidsHighway bool
+}
+
+// CompareRoutes summarizes the differences of route compared to base.
+func CompareRoutes(base, route Route) RouteComparison {
+	return RouteComparison{
+		Time:            route.Time - base.Time,
+		Distance:        route.Distance - base.Distance,
+		FuelUsed:        route.FuelUsed - base.FuelUsed,
+		TollDistance:    route.attributeDistance(AttributeToll) - base.attributeDistance(AttributeToll),
+		HighwayDistance: route.attributeDistance(AttributeLimitedAccess) - base.attributeDistance(AttributeLimitedAccess),
+		AddsTollRoad:    route.HasTollRoad && !base.HasTollRoad,
+		AvoidsTollRoad:  !route.HasTollRoad && base.HasTollRoad,
+		AddsHighway:     route.HasHighway && !base.HasHighway,
+		AvoidsHighway:   !route.HasHighway && base.HasHighway,
+	}
+}
+
+// String summarizes the comparison, eg "+4m30s, +2.1 distance, avoids toll roads"
+func (comparison RouteComparison) String() string {
+	duration := (time.Duration(comparison.Time) * time.Second).String()
+	switch {
+	case comparison.Time == 0:
+		duration = "same time"
+	case comparison.Time > 0:
+		duration = "+" + duration
+	}
+	parts := []string{duration, fmt.Sprintf("%+.1f distance", comparison.Distance)}
+	switch {
+	case comparison.AddsTollRoad:
+		parts = append(parts, "uses toll roads")
+	case comparison.AvoidsTollRoad:
+		parts = append(parts, "avoids toll roads")
+	case comparison.TollDistance != 0:
+		parts = append(parts, fmt.Sprintf("%+.1f on toll roads", comparison.TollDistance))
+	}
+	switch {
+	case comparison.AddsHighway:
+		parts = append(parts, "uses highways")
+	case comparison.AvoidsHighway:
+		parts = append(parts, "avoids highways")
+	case comparison.HighwayDistance != 0:
+		parts = append(parts, fmt.Sprintf("%+.1f on highways", comparison.HighwayDistance))
+	}
+	return strings.Join(parts, ", ")
+}
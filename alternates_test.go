@@ -0,0 +1,50 @@
+package geocoder
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const testAlternates = `{"route":{"time":3600,"distance":100,"hasTollRoad":true,"hasHighway":true,
+"legs":[{"maneuvers":[{"distance":40,"attributes":1},{"distance":60,"attributes":128}]}],
+"alternateRoutes":[{"route":{"time":3870,"distance":102.5,"hasTollRoad":false,"hasHighway":true,
+"legs":[{"maneuvers":[{"distance":22.5},{"distance":80,"attributes":128}]}]}}]}}`
+
+func TestRoutes(t *testing.T) {
+	var results DirectionsResults
+	if err := json.Unmarshal([]byte(testAlternates), &results); unexpected(err, t) {
+		return
+	}
+	routes := results.Routes()
+	if len(routes) != 2 {
+		t.Fatalf("Expected 2 routes ~ Received %d", len(routes))
+	}
+	comparison := CompareRoutes(routes[0], routes[1])
+	if comparison.Time != 270 || comparison.Distance != 2.5 || comparison.TollDistance != -40 || comparison.HighwayDistance != 20 {
+		t.Errorf("Unexpected comparison %+v", comparison)
+	}
+	if !comparison.AvoidsTollRoad || comparison.AddsTollRoad || comparison.AddsHighway || comparison.AvoidsHighway {
+		t.Errorf("Unexpected comparison flags %+v", comparison)
+	}
+	expected := "+4m30s, +2.5 distance, avoids toll roads, +20.0 on highways"
+	if comparison.String() != expected {
+		t.Errorf("Expected %s ~ Received %s", expected, comparison.String())
+	}
+}
+
+func TestAlternateRoutesURL(t *testing.T) {
+	directions := NewDirections("Amsterdam,Netherlands", []string{"Antwerp,Belgium"})
+	directions.MaxRoutes = 3
+	routeURL := directions.URL("json")
+	if !strings.HasPrefix(routeURL, directionsBaseURL+"alternateroutes?") || !strings.Contains(routeURL, "&maxRoutes=3&timeOverage=25") {
+		t.Errorf("Unexpected alternate routes url %s", routeURL)
+	}
+	body, err := json.Marshal(directions.body())
+	if unexpected(err, t) {
+		return
+	}
+	if !strings.Contains(string(body), `"maxRoutes":3,"timeOverage":25`) {
+		t.Errorf("Expected maxRoutes and timeOverage options in %s", body)
+	}
+}
@@ -0,0 +1,154 @@
+package geocoder
+
+import (
+	"math"
+	"sort"
+)
+
+// BBox is a lat/lng bounding box. A box with an upper left longitude
+// greater than its lower right longitude crosses the antimeridian.
+type BBox struct {
+	UpperLeft  LatLng `json:"ul"`
+	LowerRight LatLng `json:"lr"`
+}
+
+// NewBBox returns the smallest bounding box of the points. The box crosses
+// the antimeridian when that is smaller, eg for points around Fiji.
+func NewBBox(points []LatLng) BBox {
+	if len(points) == 0 {
+		return BBox{}
+	}
+	north, south := points[0].Lat, points[0].Lat
+	lngs := make([]float64, len(points))
+	for i, point := range points {
+		north, south = math.Max(north, point.Lat), math.Min(south, point.Lat)
+		lngs[i] = normalizeLng(point.Lng)
+	}
+	sort.Float64s(lngs)
+	// the box is everything but the largest gap between longitudes
+	west, east := lngs[0], lngs[len(lngs)-1]
+	gap := lngs[0] + 360 - lngs[len(lngs)-1]
+	for i := 1; i < len(lngs); i++ {
+		if lngs[i]-lngs[i-1] > gap {
+			gap = lngs[i] - lngs[i-1]
+			west, east = lngs[i], lngs[i-1]
+		}
+	}
+	return BBox{UpperLeft: LatLng{Lat: north, Lng: west}, LowerRight: LatLng{Lat: south, Lng: east}}
+}
+
+// lngSpan returns the degrees from west eastwards to east
+func lngSpan(west, east float64) float64 {
+	if east-west == 360 {
+		return 360
+	}
+	return math.Mod(math.Mod(east-west, 360)+360, 360)
+}
+
+// width returns the longitudes (degrees) covered by the box
+func (bbox BBox) width() float64 {
+	return lngSpan(bbox.UpperLeft.Lng, bbox.LowerRight.Lng)
+}
+
+// containsLng reports whether the longitude lies within the box
+func (bbox BBox) containsLng(lng float64) bool {
+	return lngSpan(bbox.UpperLeft.Lng, lng) <= bbox.width()
+}
+
+// coversLngs reports whether all longitudes of other lie within the box
+func (bbox BBox) coversLngs(other BBox) bool {
+	return lngSpan(bbox.UpperLeft.Lng, other.UpperLeft.Lng)+other.width() <= bbox.width() || bbox.width() == 360
+}
+
+// IsZero reports whether the box is unset
+func (bbox BBox) IsZero() bool {
+	return bbox == BBox{}
+}
+
+// CrossesAntimeridian reports whether the box crosses the 180th meridian
+func (bbox BBox) CrossesAntimeridian() bool {
+	return bbox.UpperLeft.Lng > bbox.LowerRight.Lng
+}
+
+// Contains reports whether the point lies within the box (edges included)
+func (bbox BBox) Contains(point LatLng) bool {
+	return point.Lat <= bbox.UpperLeft.Lat && point.Lat >= bbox.LowerRight.Lat && bbox.containsLng(point.Lng)
+}
+
+// Intersects reports whether the boxes have at least one point in common
+func (bbox BBox) Intersects(other BBox) bool {
+	if other.LowerRight.Lat > bbox.UpperLeft.Lat || other.UpperLeft.Lat < bbox.LowerRight.Lat {
+		return false
+	}
+	return bbox.containsLng(other.UpperLeft.Lng) || other.containsLng(bbox.UpperLeft.Lng)
+}
+
+// Union returns the smallest box containing both boxes
+func (bbox BBox) Union(other BBox) BBox {
+	if bbox.IsZero() {
+		return other
+	}
+	if other.IsZero() {
+		return bbox
+	}
+	union := BBox{
+		UpperLeft:  LatLng{Lat: math.Max(bbox.UpperLeft.Lat, other.UpperLeft.Lat)},
+		LowerRight: LatLng{Lat: math.Min(bbox.LowerRight.Lat, other.LowerRight.Lat)},
+	}
+	// the smallest of the boxes spanning from the west of one box to the east of the other
+	best := 361.0
+	for _, candidate := range [][2]float64{
+		{bbox.UpperLeft.Lng, bbox.LowerRight.Lng},
+		{other.UpperLeft.Lng, other.LowerRight.Lng},
+		{bbox.UpperLeft.Lng, other.LowerRight.Lng},
+		{other.UpperLeft.Lng, bbox.LowerRight.Lng},
+	} {
+		box := BBox{UpperLeft: LatLng{Lng: candidate[0]}, LowerRight: LatLng{Lng: candidate[1]}}
+		if width := box.width(); width < best && box.coversLngs(bbox) && box.coversLngs(other) {
+			best = width
+			union.UpperLeft.Lng, union.LowerRight.Lng = candidate[0], candidate[1]
+		}
+	}
+	if best > 360 {
+		union.UpperLeft.Lng, union.LowerRight.Lng = -180, 180
+	}
+	return union
+}
+
+// Expand returns the box grown by a distance (in unit) on all sides.
+// A box reaching a pole covers all longitudes.
+func (bbox BBox) Expand(distance float64, unit Unit) BBox {
+	angle := distance * unit.meters() / earthRadius
+	north := bbox.UpperLeft.Lat + degrees(angle)
+	south := bbox.LowerRight.Lat - degrees(angle)
+	if north >= 90 || south <= -90 {
+		return BBox{UpperLeft: LatLng{Lat: math.Min(north, 90), Lng: -180}, LowerRight: LatLng{Lat: math.Max(south, -90), Lng: 180}}
+	}
+	// the longitude difference is largest at the latitude closest to a pole
+	lat := radians(math.Max(math.Abs(north), math.Abs(south)))
+	dLng := degrees(math.Asin(math.Min(1, math.Sin(angle)/math.Cos(lat))))
+	if bbox.width()+2*dLng >= 360 {
+		return BBox{UpperLeft: LatLng{Lat: north, Lng: -180}, LowerRight: LatLng{Lat: south, Lng: 180}}
+	}
+	east := normalizeLng(bbox.LowerRight.Lng + dLng)
+	if east == -180 {
+		east = 180
+	}
+	return BBox{
+		UpperLeft:  LatLng{Lat: north, Lng: normalizeLng(bbox.UpperLeft.Lng - dLng)},
+		LowerRight: LatLng{Lat: south, Lng: east},
+	}
+}
+
+// Center returns the center of the box
+func (bbox BBox) Center() LatLng {
+	return LatLng{
+		Lat: (bbox.UpperLeft.Lat + bbox.LowerRight.Lat) / 2,
+		Lng: normalizeLng(bbox.UpperLeft.Lng + bbox.width()/2),
+	}
+}
+
+// String formats the box as "ulLat,ulLng,lrLat,lrLng" (as the geocoding api expects)
+func (bbox BBox) String() string {
+	return bbox.UpperLeft.String() + "," + bbox.LowerRight.String()
+}
@@ -0,0 +1,71 @@
+package geocoder
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNewBBox(t *testing.T) {
+	bbox := NewBBox([]LatLng{{Lat: 51.2, Lng: 4.4}, {Lat: 52.4, Lng: 4.9}, {Lat: 50.8, Lng: 4.3}})
+	expected := BBox{UpperLeft: LatLng{Lat: 52.4, Lng: 4.3}, LowerRight: LatLng{Lat: 50.8, Lng: 4.9}}
+	if bbox != expected || bbox.CrossesAntimeridian() {
+		t.Errorf("Expected %v ~ Received %v", expected, bbox)
+	}
+	// Fiji lies on both sides of the antimeridian
+	bbox = NewBBox([]LatLng{{Lat: -16.5, Lng: 179.5}, {Lat: -18.1, Lng: 178.4}, {Lat: -17.0, Lng: -179.9}})
+	expected = BBox{UpperLeft: LatLng{Lat: -16.5, Lng: 178.4}, LowerRight: LatLng{Lat: -18.1, Lng: -179.9}}
+	if bbox != expected || !bbox.CrossesAntimeridian() {
+		t.Errorf("Expected %v ~ Received %v", expected, bbox)
+	}
+	if !bbox.Contains(LatLng{Lat: -17, Lng: 180}) || bbox.Contains(LatLng{Lat: -17, Lng: 0}) {
+		t.Errorf("Unexpected contains of %v", bbox)
+	}
+	if center := bbox.Center(); math.Abs(center.Lng-179.25) > 1e-9 || math.Abs(center.Lat+17.3) > 1e-9 {
+		t.Errorf("Expected (-17.3, 179.25) ~ Received %v", center)
+	}
+	if !NewBBox(nil).IsZero() {
+		t.Errorf("Expected a zero box")
+	}
+}
+
+func TestBBoxIntersectsUnion(t *testing.T) {
+	a := BBox{UpperLeft: LatLng{Lat: 10, Lng: 170}, LowerRight: LatLng{Lat: 0, Lng: -170}}
+	b := BBox{UpperLeft: LatLng{Lat: 5, Lng: -175}, LowerRight: LatLng{Lat: -5, Lng: -160}}
+	c := BBox{UpperLeft: LatLng{Lat: 5, Lng: 0}, LowerRight: LatLng{Lat: -5, Lng: 10}}
+	if !a.Intersects(b) || !b.Intersects(a) || a.Intersects(c) || c.Intersects(a) {
+		t.Errorf("Unexpected intersections of %v, %v, %v", a, b, c)
+	}
+	expected := BBox{UpperLeft: LatLng{Lat: 10, Lng: 170}, LowerRight: LatLng{Lat: -5, Lng: -160}}
+	if union := a.Union(b); union != expected {
+		t.Errorf("Expected %v ~ Received %v", expected, union)
+	}
+	expected = BBox{UpperLeft: LatLng{Lat: 5, Lng: -175}, LowerRight: LatLng{Lat: -5, Lng: 10}}
+	if union := c.Union(b); union != expected {
+		t.Errorf("Expected %v ~ Received %v", expected, union)
+	}
+	if union := (BBox{}).Union(c); union != c {
+		t.Errorf("Expected %v ~ Received %v", c, union)
+	}
+}
+
+func TestBBoxExpand(t *testing.T) {
+	degree := earthRadius * math.Pi / 180 / 1000
+	bbox := BBox{UpperLeft: LatLng{Lat: 1, Lng: 178}, LowerRight: LatLng{Lat: -1, Lng: 179}}.Expand(degree, Kilometers)
+	if math.Abs(bbox.UpperLeft.Lat-2) > 1e-9 || math.Abs(bbox.LowerRight.Lat+2) > 1e-9 {
+		t.Errorf("Expected latitudes 2, -2 ~ Received %v", bbox)
+	}
+	if bbox.UpperLeft.Lng > 177 || bbox.LowerRight.Lng > -179 || !bbox.CrossesAntimeridian() {
+		t.Errorf("Expected a box across the antimeridian ~ Received %v", bbox)
+	}
+	bbox = BBox{UpperLeft: LatLng{Lat: 89, Lng: 0}, LowerRight: LatLng{Lat: 88, Lng: 1}}.Expand(200, Kilometers)
+	if bbox.UpperLeft.Lat != 90 || bbox.UpperLeft.Lng != -180 || bbox.LowerRight.Lng != 180 {
+		t.Errorf("Expected a box around the pole ~ Received %v", bbox)
+	}
+}
+
+func TestBBoxString(t *testing.T) {
+	bbox := BBox{UpperLeft: LatLng{Lat: 47.7, Lng: -122.5}, LowerRight: LatLng{Lat: 47.5, Lng: -122.2}}
+	if bbox.String() != "47.7,-122.5,47.5,-122.2" {
+		t.Errorf("Expected 47.7,-122.5,47.5,-122.2 ~ Received %s", bbox)
+	}
+}
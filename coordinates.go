@@ -0,0 +1,501 @@
+/* Parsing and formatting of coordinates as decimal degrees,
+degrees-minutes-seconds (DMS), UTM and MGRS on the WGS84 ellipsoid.
+
+Geocode and FullGeocode recognize unambiguous coordinate strings: Geocode
+returns them without a request, FullGeocode reverse geocodes them. Grid style
+addresses like "50 S 100 E" are geocoded as addresses.
+
+Example:
+
+point, err := ParseCoordinates(`47°36'13.8"N 122°19'48.2"W`)
+dms := point.DMS() // 47°36'13.8"N 122°19'48.2"W
+utm, err := point.UTM()
+mgrs, err := point.MGRS(5)
+
+*/
+
+package geocoder
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// UTM scale factor at the central meridian and false easting and northing
+const (
+	utmScale         = 0.9996
+	utmFalseEasting  = 500000.0
+	utmFalseNorthing = 10000000.0
+)
+
+// latitude bands of 8° from 80°S (band X is 12°)
+const utmBands = "CDEFGHJKLMNPQRSTUVWX"
+
+// MGRS 100 km square column (3 sets of 8) and row letters
+const (
+	mgrsColumns = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+	mgrsRows    = "ABCDEFGHJKLMNPQRSTUV"
+)
+
+var mgrsPattern = regexp.MustCompile(`^(\d{1,2})([C-HJ-NP-X])([A-HJ-NP-Z])([A-HJ-NP-V])(\d*)$`)
+
+// patterns of strings which are clearly coordinates rather than addresses
+var (
+	decimalPattern    = regexp.MustCompile(`\d\.\d`)
+	signedPairPattern = regexp.MustCompile(`^[-+]?\d+(\.\d+)?\s*,\s*[-+]?\d+(\.\d+)?$`)
+	utmQueryPattern   = regexp.MustCompile(`^\d{1,2}[C-HJ-NP-X]\s+\d{6}(\.\d+)?\s+\d{6,8}(\.\d+)?$`)
+	mgrsQueryPattern  = regexp.MustCompile(`^\d{1,2}[C-HJ-NP-X] ?[A-HJ-NP-Z][A-HJ-NP-V] ?(\d+)(?: (\d+))?$`)
+)
+
+// coordinateToken is a number, a hemisphere letter or a comma of a DMS string
+type coordinateToken struct {
+	number     float64
+	hemisphere byte
+	comma      bool
+}
+
+// coordinateTokens splits a DMS string into tokens. The degree, minute and
+// second symbols separate the numbers like spaces do.
+func coordinateTokens(s string) ([]coordinateToken, error) {
+	var tokens []coordinateToken
+	runes := []rune(strings.ToUpper(strings.TrimSpace(s)))
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r) || strings.ContainsRune(`°º'"′″`, r):
+			i++
+		case r == ',':
+			tokens = append(tokens, coordinateToken{comma: true})
+			i++
+		case unicode.IsDigit(r) || r == '.' || r == '-' || r == '+':
+			j := i + 1
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			number, err := strconv.ParseFloat(string(runes[i:j]), 64)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid coordinate number %q", string(runes[i:j]))
+			}
+			tokens = append(tokens, coordinateToken{number: number})
+			i = j
+		case unicode.IsLetter(r):
+			j := i + 1
+			for j < len(runes) && unicode.IsLetter(runes[j]) {
+				j++
+			}
+			if j-i != 1 || !strings.ContainsRune("NSEW", r) {
+				return nil, fmt.Errorf("Invalid hemisphere %q", string(runes[i:j]))
+			}
+			tokens = append(tokens, coordinateToken{hemisphere: byte(r)})
+			i = j
+		default:
+			return nil, fmt.Errorf("Invalid coordinate character %q", r)
+		}
+	}
+	return tokens, nil
+}
+
+// coordinateGroups splits the tokens into the two coordinates: at the
+// hemisphere letters (before or after the numbers), at a comma or in half.
+func coordinateGroups(tokens []coordinateToken) [][]coordinateToken {
+	var groups [][]coordinateToken
+	var group []coordinateToken
+	letters, commas, numbers := 0, 0, 0
+	for _, token := range tokens {
+		switch {
+		case token.hemisphere != 0:
+			letters++
+		case token.comma:
+			commas++
+		default:
+			numbers++
+		}
+	}
+	prefix := len(tokens) > 0 && tokens[0].hemisphere != 0
+	for _, token := range tokens {
+		switch {
+		case letters > 0 && token.hemisphere != 0 && prefix:
+			if len(group) > 0 {
+				groups = append(groups, group)
+			}
+			group = []coordinateToken{token}
+		case letters > 0 && token.hemisphere != 0:
+			groups = append(groups, append(group, token))
+			group = nil
+		case token.comma:
+			if letters == 0 {
+				groups = append(groups, group)
+				group = nil
+			}
+		case letters == 0 && commas == 0 && len(group) == numbers/2 && len(groups) == 0:
+			groups = append(groups, group)
+			group = []coordinateToken{token}
+		default:
+			group = append(group, token)
+		}
+	}
+	if len(group) > 0 || letters == 0 {
+		groups = append(groups, group)
+	}
+	if letters == 0 && commas == 0 && numbers%2 != 0 {
+		return nil
+	}
+	return groups
+}
+
+// coordinateValue returns the decimal degrees and hemisphere (if any) of a
+// group of 1 to 3 numbers (degrees, minutes and seconds).
+func coordinateValue(group []coordinateToken) (float64, byte, error) {
+	var numbers []float64
+	var hemisphere byte
+	for _, token := range group {
+		if token.hemisphere != 0 {
+			hemisphere = token.hemisphere
+		} else {
+			numbers = append(numbers, token.number)
+		}
+	}
+	if len(numbers) == 0 || len(numbers) > 3 {
+		return 0, 0, fmt.Errorf("Expected degrees, minutes and seconds ~ Received %d numbers", len(numbers))
+	}
+	negative := math.Signbit(numbers[0])
+	value := math.Abs(numbers[0])
+	for i, number := range numbers[1:] {
+		if math.Signbit(number) || number >= 60 {
+			return 0, 0, fmt.Errorf("Invalid minutes or seconds %v", number)
+		}
+		value += number / math.Pow(60, float64(i+1))
+	}
+	if negative && hemisphere != 0 {
+		return 0, 0, fmt.Errorf("Negative coordinate with hemisphere %c", hemisphere)
+	}
+	if negative || hemisphere == 'S' || hemisphere == 'W' {
+		value = -value
+	}
+	return value, hemisphere, nil
+}
+
+// ParseDMS parses a latitude and longitude in decimal degrees, degrees and
+// decimal minutes or degrees, minutes and seconds, with hemisphere letters or
+// signs, eg `47°36'13.8"N 122°19'48.2"W`, "N 47 36.23 W 122 19.8" or
+// "47.6038, -122.3301". Without hemisphere letters the latitude comes first.
+func ParseDMS(s string) (LatLng, error) {
+	tokens, err := coordinateTokens(s)
+	if err != nil {
+		return LatLng{}, err
+	}
+	groups := coordinateGroups(tokens)
+	if len(groups) != 2 {
+		return LatLng{}, fmt.Errorf("Expected a latitude and a longitude in %q", s)
+	}
+	var values [2]float64
+	var hemispheres [2]byte
+	for i, group := range groups {
+		if values[i], hemispheres[i], err = coordinateValue(group); err != nil {
+			return LatLng{}, err
+		}
+	}
+	// the longitude comes first if either coordinate says so
+	if hemispheres[0] == 'E' || hemispheres[0] == 'W' || hemispheres[1] == 'N' || hemispheres[1] == 'S' {
+		values[0], values[1] = values[1], values[0]
+		hemispheres[0], hemispheres[1] = hemispheres[1], hemispheres[0]
+	}
+	if hemispheres[0] == 'E' || hemispheres[0] == 'W' || hemispheres[1] == 'N' || hemispheres[1] == 'S' {
+		return LatLng{}, fmt.Errorf("Expected a latitude and a longitude in %q", s)
+	}
+	point := LatLng{Lat: values[0], Lng: values[1]}
+	if math.Abs(point.Lat) > 90 || math.Abs(point.Lng) > 180 {
+		return LatLng{}, fmt.Errorf("Coordinates out of range in %q", s)
+	}
+	return point, nil
+}
+
+// formatDMS formats a coordinate as degrees, minutes and seconds (tenths)
+func formatDMS(value float64, positive, negative byte) string {
+	hemisphere := positive
+	if value < 0 {
+		hemisphere = negative
+	}
+	tenths := int64(math.Round(math.Abs(value) * 36000))
+	return fmt.Sprintf(`%d°%d'%.1f"%c`, tenths/36000, tenths%36000/600, float64(tenths%600)/10, hemisphere)
+}
+
+// DMS formats the point as degrees, minutes and seconds, eg `47°36'13.8"N 122°19'48.2"W`
+func (latLng LatLng) DMS() string {
+	return formatDMS(latLng.Lat, 'N', 'S') + " " + formatDMS(latLng.Lng, 'E', 'W')
+}
+
+// UTM is a point in the Universal Transverse Mercator grid
+type UTM struct {
+	// Zone (1 to 60) and latitude band (C to X, N and up on the northern hemisphere)
+	Zone int
+	Band byte
+	// Meters (false easting 500000, southern false northing 10000000)
+	Easting, Northing float64
+}
+
+// utmZone returns the zone of a point, including the exceptions for
+// Norway and Svalbard.
+func utmZone(point LatLng) int {
+	lng := normalizeLng(point.Lng)
+	zone := int(math.Floor((lng+180)/6)) + 1
+	if point.Lat >= 56 && point.Lat < 64 && lng >= 3 && lng < 12 {
+		return 32
+	}
+	if point.Lat >= 72 {
+		switch {
+		case lng >= 0 && lng < 9:
+			return 31
+		case lng >= 9 && lng < 21:
+			return 33
+		case lng >= 21 && lng < 33:
+			return 35
+		case lng >= 33 && lng < 42:
+			return 37
+		}
+	}
+	return zone
+}
+
+// utmBand returns the latitude band of a latitude within [-80, 84]
+func utmBand(lat float64) byte {
+	index := int(math.Floor((lat + 80) / 8))
+	if index > len(utmBands)-1 {
+		index = len(utmBands) - 1
+	}
+	return utmBands[index]
+}
+
+// centralMeridian returns the longitude of the central meridian of a zone
+func centralMeridian(zone int) float64 {
+	return float64(zone-1)*6 - 180 + 3
+}
+
+// utmProject projects a point in a zone (Snyder's series)
+func utmProject(point LatLng, zone int) (easting, northing float64) {
+	e2 := wgs84F * (2 - wgs84F)
+	ep2 := e2 / (1 - e2)
+	lat := radians(point.Lat)
+	sin, cos, tan := math.Sin(lat), math.Cos(lat), math.Tan(lat)
+	n := wgs84A / math.Sqrt(1-e2*sin*sin)
+	t := tan * tan
+	c := ep2 * cos * cos
+	a := cos * radians(normalizeLng(point.Lng-centralMeridian(zone)))
+	m := wgs84A * ((1-e2/4-3*e2*e2/64-5*e2*e2*e2/256)*lat -
+		(3*e2/8+3*e2*e2/32+45*e2*e2*e2/1024)*math.Sin(2*lat) +
+		(15*e2*e2/256+45*e2*e2*e2/1024)*math.Sin(4*lat) -
+		(35*e2*e2*e2/3072)*math.Sin(6*lat))
+	easting = utmScale*n*(a+(1-t+c)*math.Pow(a, 3)/6+(5-18*t+t*t+72*c-58*ep2)*math.Pow(a, 5)/120) + utmFalseEasting
+	northing = utmScale * (m + n*tan*(a*a/2+(5-t+9*c+4*c*c)*math.Pow(a, 4)/24+
+		(61-58*t+t*t+600*c-330*ep2)*math.Pow(a, 6)/720))
+	if point.Lat < 0 {
+		northing += utmFalseNorthing
+	}
+	return easting, northing
+}
+
+// UTM converts the point into UTM coordinates. UTM is defined from
+// 80°S to 84°N only.
+func (latLng LatLng) UTM() (UTM, error) {
+	if math.IsNaN(latLng.Lat) || math.IsNaN(latLng.Lng) || latLng.Lat < -80 || latLng.Lat > 84 {
+		return UTM{}, fmt.Errorf("Latitude %v outside of the UTM grid (80°S to 84°N)", latLng.Lat)
+	}
+	zone := utmZone(latLng)
+	easting, northing := utmProject(latLng, zone)
+	return UTM{Zone: zone, Band: utmBand(latLng.Lat), Easting: easting, Northing: northing}, nil
+}
+
+// validate checks the zone and band
+func (utm UTM) validate() error {
+	if utm.Zone < 1 || utm.Zone > 60 {
+		return fmt.Errorf("Invalid UTM zone %d", utm.Zone)
+	}
+	if utm.Band == 0 || strings.IndexByte(utmBands, utm.Band) < 0 {
+		return fmt.Errorf("Invalid UTM latitude band %q", utm.Band)
+	}
+	return nil
+}
+
+// LatLng converts the UTM coordinates into a point
+func (utm UTM) LatLng() (LatLng, error) {
+	if err := utm.validate(); err != nil {
+		return LatLng{}, err
+	}
+	e2 := wgs84F * (2 - wgs84F)
+	ep2 := e2 / (1 - e2)
+	x := utm.Easting - utmFalseEasting
+	y := utm.Northing
+	if utm.Band < 'N' {
+		y -= utmFalseNorthing
+	}
+	mu := y / utmScale / (wgs84A * (1 - e2/4 - 3*e2*e2/64 - 5*e2*e2*e2/256))
+	e1 := (1 - math.Sqrt(1-e2)) / (1 + math.Sqrt(1-e2))
+	lat1 := mu + (3*e1/2-27*math.Pow(e1, 3)/32)*math.Sin(2*mu) +
+		(21*e1*e1/16-55*math.Pow(e1, 4)/32)*math.Sin(4*mu) +
+		(151*math.Pow(e1, 3)/96)*math.Sin(6*mu) +
+		(1097*math.Pow(e1, 4)/512)*math.Sin(8*mu)
+	sin, cos, tan := math.Sin(lat1), math.Cos(lat1), math.Tan(lat1)
+	n := wgs84A / math.Sqrt(1-e2*sin*sin)
+	t := tan * tan
+	c := ep2 * cos * cos
+	r := wgs84A * (1 - e2) / math.Pow(1-e2*sin*sin, 1.5)
+	d := x / (n * utmScale)
+	lat := lat1 - (n*tan/r)*(d*d/2-(5+3*t+10*c-4*c*c-9*ep2)*math.Pow(d, 4)/24+
+		(61+90*t+298*c+45*t*t-252*ep2-3*c*c)*math.Pow(d, 6)/720)
+	lng := (d - (1+2*t+c)*math.Pow(d, 3)/6 + (5-2*c+28*t-3*c*c+8*ep2+24*t*t)*math.Pow(d, 5)/120) / cos
+	return LatLng{Lat: degrees(lat), Lng: normalizeLng(centralMeridian(utm.Zone) + degrees(lng))}, nil
+}
+
+// String formats the UTM coordinates in meters, eg "10T 550200 5272700"
+func (utm UTM) String() string {
+	return fmt.Sprintf("%d%c %.0f %.0f", utm.Zone, utm.Band, math.Floor(utm.Easting), math.Floor(utm.Northing))
+}
+
+// ParseUTM parses UTM coordinates as "10T 550200 5272700" or "10 T 550200 5272700"
+func ParseUTM(s string) (UTM, error) {
+	fields := strings.Fields(strings.ToUpper(s))
+	if len(fields) == 4 {
+		fields = append([]string{fields[0] + fields[1]}, fields[2:]...)
+	}
+	if len(fields) != 3 || len(fields[0]) < 2 {
+		return UTM{}, fmt.Errorf("Invalid UTM coordinates %q", s)
+	}
+	zone, err := strconv.Atoi(fields[0][:len(fields[0])-1])
+	if err != nil {
+		return UTM{}, fmt.Errorf("Invalid UTM zone %q", fields[0])
+	}
+	utm := UTM{Zone: zone, Band: fields[0][len(fields[0])-1]}
+	if err := utm.validate(); err != nil {
+		return UTM{}, err
+	}
+	if utm.Easting, err = strconv.ParseFloat(fields[1], 64); err != nil || utm.Easting <= 0 || utm.Easting >= 1000000 {
+		return UTM{}, fmt.Errorf("Invalid UTM easting %q", fields[1])
+	}
+	if utm.Northing, err = strconv.ParseFloat(fields[2], 64); err != nil || utm.Northing < 0 || utm.Northing > utmFalseNorthing {
+		return UTM{}, fmt.Errorf("Invalid UTM northing %q", fields[2])
+	}
+	return utm, nil
+}
+
+// MGRS formats the point as a military grid reference with precision digits
+// per coordinate: 1 (10 km) to 5 (1 m), eg "31NAA6602100000".
+func (latLng LatLng) MGRS(precision int) (string, error) {
+	utm, err := latLng.UTM()
+	if err != nil {
+		return "", err
+	}
+	if precision < 1 {
+		precision = 1
+	}
+	if precision > 5 {
+		precision = 5
+	}
+	square := int(utm.Easting / 100000)
+	if square < 1 || square > 8 {
+		return "", fmt.Errorf("Easting %v outside of the MGRS columns", utm.Easting)
+	}
+	column := mgrsColumns[(utm.Zone-1)%3*8+square-1]
+	row := mgrsRows[(int(utm.Northing/100000)%20+mgrsRowOffset(utm.Zone))%20]
+	cell := math.Pow10(5 - precision)
+	easting := int(math.Floor(math.Mod(utm.Easting, 100000) / cell))
+	northing := int(math.Floor(math.Mod(utm.Northing, 100000) / cell))
+	return fmt.Sprintf("%d%c%c%c%0*d%0*d", utm.Zone, utm.Band, column, row, precision, easting, precision, northing), nil
+}
+
+// mgrsRowOffset returns the offset of the row letters of a zone
+func mgrsRowOffset(zone int) int {
+	if zone%2 == 0 {
+		return 5
+	}
+	return 0
+}
+
+// ParseMGRS parses a military grid reference, eg "31NAA6602100000" or
+// "31N AA 66021 00000", and returns the center of the referenced square.
+// The polar (UPS) regions are not supported.
+func ParseMGRS(s string) (LatLng, error) {
+	match := mgrsPattern.FindStringSubmatch(strings.Join(strings.Fields(strings.ToUpper(s)), ""))
+	if match == nil || len(match[5])%2 != 0 || len(match[5]) > 10 {
+		return LatLng{}, fmt.Errorf("Invalid MGRS reference %q", s)
+	}
+	zone, _ := strconv.Atoi(match[1])
+	utm := UTM{Zone: zone, Band: match[2][0]}
+	if err := utm.validate(); err != nil {
+		return LatLng{}, err
+	}
+	column := strings.IndexByte(mgrsColumns, match[3][0]) - (zone-1)%3*8
+	if column < 0 || column > 7 {
+		return LatLng{}, fmt.Errorf("Invalid MGRS column %s in zone %d", match[3], zone)
+	}
+	row := (strings.IndexByte(mgrsRows, match[4][0]) - mgrsRowOffset(zone) + 20) % 20
+	utm.Easting = float64(column+1) * 100000
+	utm.Northing = float64(row) * 100000
+	// the rows repeat every 2000 km, the southern edge of the band (lowest
+	// at the central meridian in the north, at the zone edge in the south)
+	// tells which repetition
+	south := -80 + 8*float64(strings.IndexByte(utmBands, utm.Band))
+	_, center := utmProject(LatLng{Lat: south, Lng: centralMeridian(zone)}, zone)
+	_, edge := utmProject(LatLng{Lat: south, Lng: centralMeridian(zone) + 3}, zone)
+	bandNorthing := math.Min(center, edge)
+	for utm.Northing < math.Floor(bandNorthing/100000)*100000 {
+		utm.Northing += 2000000
+	}
+	digits := match[5]
+	precision := len(digits) / 2
+	cell := math.Pow10(5 - precision)
+	if precision > 0 {
+		easting, _ := strconv.Atoi(digits[:precision])
+		northing, _ := strconv.Atoi(digits[precision:])
+		utm.Easting += float64(easting) * cell
+		utm.Northing += float64(northing) * cell
+	}
+	utm.Easting += cell / 2
+	utm.Northing += cell / 2
+	return utm.LatLng()
+}
+
+// ParseCoordinates parses a point in any of the supported formats: decimal
+// degrees or DMS (see ParseDMS), UTM (see ParseUTM) or MGRS (see ParseMGRS).
+func ParseCoordinates(s string) (LatLng, error) {
+	if point, err := ParseDMS(s); err == nil {
+		return point, nil
+	}
+	if utm, err := ParseUTM(s); err == nil {
+		return utm.LatLng()
+	}
+	if point, err := ParseMGRS(s); err == nil {
+		return point, nil
+	}
+	return LatLng{}, fmt.Errorf("Invalid coordinates %q", s)
+}
+
+// coordinateQuery returns the point of a geocoding query which is clearly a
+// coordinate string: DMS with degree, minute or second symbols or decimal
+// numbers, a comma separated pair, UTM or MGRS grouped like "4QFJ1234" or
+// "4Q FJ 12 34" with at least 4 digits. Grid style addresses like "50 S 100 E"
+// or "12 SUB 1234" are not coordinates.
+func coordinateQuery(s string) (LatLng, bool) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	switch {
+	case strings.ContainsAny(s, `°º'"′″`) || decimalPattern.MatchString(s) || signedPairPattern.MatchString(s):
+		point, err := ParseDMS(s)
+		return point, err == nil
+	case utmQueryPattern.MatchString(s):
+		utm, err := ParseUTM(s)
+		if err != nil {
+			return LatLng{}, false
+		}
+		point, err := utm.LatLng()
+		return point, err == nil
+	}
+	match := mgrsQueryPattern.FindStringSubmatch(strings.Join(strings.Fields(s), " "))
+	if match != nil && len(match[1]+match[2]) >= 4 && (match[2] == "" || len(match[1]) == len(match[2])) {
+		point, err := ParseMGRS(s)
+		return point, err == nil
+	}
+	return LatLng{}, false
+}
@@ -0,0 +1,162 @@
+package geocoder
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseDMS(t *testing.T) {
+	expected := LatLng{Lat: 47.603833, Lng: -122.330056}
+	for _, s := range []string{
+		`47°36'13.8"N 122°19'48.2"W`,
+		`122°19'48.2"W, 47°36'13.8"N`,
+		"N 47 36 13.8 W 122 19 48.2",
+		"47 36.23N 122 19.80333W",
+		"47.603833, -122.330056",
+		"47.603833 -122.330056",
+		"47 36 13.8 -122 19 48.2",
+	} {
+		point, err := ParseDMS(s)
+		if unexpected(err, t) {
+			continue
+		}
+		if math.Abs(point.Lat-expected.Lat) > 1e-5 || math.Abs(point.Lng-expected.Lng) > 1e-5 {
+			t.Errorf("%s: Expected %v ~ Received %v", s, expected, point)
+		}
+	}
+	for _, s := range []string{"Seattle WA", "90210", "47 N 122 N", "95 N 10 E", "47 61 N 122 W", "-47 N 122 W", "1, 2, 3", ""} {
+		if point, err := ParseDMS(s); err == nil {
+			t.Errorf("%s: Expected an error ~ Received %v", s, point)
+		}
+	}
+	if dms := (LatLng{Lat: 47.603833, Lng: -122.330056}).DMS(); dms != `47°36'13.8"N 122°19'48.2"W` {
+		t.Errorf(`Expected 47°36'13.8"N 122°19'48.2"W ~ Received %v`, dms)
+	}
+	if dms := (LatLng{Lat: -33.999999, Lng: 151.2}).DMS(); dms != `34°0'0.0"S 151°12'0.0"E` {
+		t.Errorf(`Expected 34°0'0.0"S 151°12'0.0"E ~ Received %v`, dms)
+	}
+}
+
+func TestUTM(t *testing.T) {
+	utm, err := LatLng{}.UTM()
+	if unexpected(err, t) {
+		return
+	}
+	if utm.Zone != 31 || utm.Band != 'N' || math.Abs(utm.Easting-166021.44) > 0.01 || math.Abs(utm.Northing) > 0.01 {
+		t.Errorf("Expected 31N 166021.44 0 ~ Received %v", utm)
+	}
+	// zone exceptions of Norway and Svalbard
+	if utm, _ := (LatLng{Lat: 60, Lng: 5}).UTM(); utm.Zone != 32 {
+		t.Errorf("Norway: Expected zone 32 ~ Received %v", utm)
+	}
+	if utm, _ := (LatLng{Lat: 78, Lng: 15}).UTM(); utm.Zone != 33 || utm.Band != 'X' {
+		t.Errorf("Svalbard: Expected zone 33X ~ Received %v", utm)
+	}
+	for _, point := range []LatLng{{Lat: 47.603833, Lng: -122.330056}, {Lat: -33.8688, Lng: 151.2093}, {Lat: 83.5, Lng: -40}, {Lat: -79.9, Lng: 179.9}} {
+		utm, err := point.UTM()
+		if unexpected(err, t) {
+			continue
+		}
+		parsed, err := ParseUTM(utm.String())
+		if unexpected(err, t) {
+			continue
+		}
+		back, err := parsed.LatLng()
+		if unexpected(err, t) {
+			continue
+		}
+		if back.Distance(point, Kilometers) > 0.002 {
+			t.Errorf("%v: Expected %v ~ Received %v", utm, point, back)
+		}
+	}
+	if _, err := (LatLng{Lat: 85, Lng: 0}).UTM(); err == nil {
+		t.Errorf("Expected an error beyond 84°N")
+	}
+	for _, s := range []string{"10T 550200", "61T 550200 5272700", "10I 550200 5272700", "10T -5 5272700"} {
+		if _, err := ParseUTM(s); err == nil {
+			t.Errorf("%s: Expected an error", s)
+		}
+	}
+}
+
+func TestMGRS(t *testing.T) {
+	mgrs, err := LatLng{}.MGRS(5)
+	if unexpected(err, t) {
+		return
+	}
+	if mgrs != "31NAA6602100000" {
+		t.Errorf("Expected 31NAA6602100000 ~ Received %v", mgrs)
+	}
+	if mgrs, _ := (LatLng{}).MGRS(2); mgrs != "31NAA6600" {
+		t.Errorf("Expected 31NAA6600 ~ Received %v", mgrs)
+	}
+	for _, point := range []LatLng{{Lat: 47.603833, Lng: -122.330056}, {Lat: -33.8688, Lng: 151.2093}, {Lat: 60.39, Lng: 5.32}, {Lat: -54.8, Lng: -68.3}, {Lat: 0.5, Lng: 36.8}} {
+		mgrs, err := point.MGRS(5)
+		if unexpected(err, t) {
+			continue
+		}
+		back, err := ParseMGRS(mgrs)
+		if unexpected(err, t) {
+			continue
+		}
+		if back.Distance(point, Kilometers) > 0.002 {
+			t.Errorf("%v: Expected %v ~ Received %v", mgrs, point, back)
+		}
+	}
+	// the center of a 10 km square
+	center, err := ParseMGRS("31N AA 6 0")
+	if !unexpected(err, t) && center.Distance(LatLng{}, Kilometers) < 1 {
+		t.Errorf("Expected the center of the square ~ Received %v", center)
+	}
+	for _, s := range []string{"31NAA660210000", "31NIA6602100000", "31NAW66021", "61NAA66021"} {
+		if _, err := ParseMGRS(s); err == nil {
+			t.Errorf("%s: Expected an error", s)
+		}
+	}
+}
+
+func TestParseCoordinates(t *testing.T) {
+	seattle := LatLng{Lat: 47.603833, Lng: -122.330056}
+	utm, _ := seattle.UTM()
+	mgrs, _ := seattle.MGRS(5)
+	for _, s := range []string{`47°36'13.8"N 122°19'48.2"W`, utm.String(), mgrs} {
+		point, err := ParseCoordinates(s)
+		if unexpected(err, t) {
+			continue
+		}
+		if point.Distance(seattle, Kilometers) > 0.002 {
+			t.Errorf("%s: Expected %v ~ Received %v", s, seattle, point)
+		}
+	}
+	if _, err := ParseCoordinates("1600 Pennsylvania Ave NW, Washington DC"); err == nil {
+		t.Errorf("Expected an error for an address")
+	}
+}
+
+func TestCoordinateQuery(t *testing.T) {
+	seattle := LatLng{Lat: 47.603833, Lng: -122.330056}
+	utm, _ := seattle.UTM()
+	mgrs, _ := seattle.MGRS(5)
+	for _, s := range []string{`47°36'13.8"N 122°19'48.2"W`, "47.603833 -122.330056", "47.603833N 122.330056W", "47,-122", utm.String(), mgrs, "4Q FJ 12 34", "4QFJ 1234"} {
+		if _, ok := coordinateQuery(s); !ok {
+			t.Errorf("%s: Expected coordinates", s)
+		}
+	}
+	// grid style street addresses and bare numbers are geocoded
+	for _, s := range []string{"50 S 100 E", "400 S 200 W", "12 34", "10 20", "4QFJ", "4QFJ12", "4 Qfj 12", "12 SUB 1234", "4Q FJ 123 4", "1600 Pennsylvania Ave NW"} {
+		if point, ok := coordinateQuery(s); ok {
+			t.Errorf("%s: Expected an address ~ Received %v", s, point)
+		}
+	}
+}
+
+func TestGeocodeCoordinates(t *testing.T) {
+	// coordinates are returned without a request
+	lat, lng, err := Geocode(`47°36'13.8"N 122°19'48.2"W`)
+	if unexpected(err, t) {
+		return
+	}
+	if math.Abs(lat-47.603833) > 1e-5 || math.Abs(lng+122.330056) > 1e-5 {
+		t.Errorf("Expected (47.603833, -122.330056) ~ Received (%f, %f)", lat, lng)
+	}
+}
@@ -5,12 +5,13 @@ Reference: http://open.mapquestapi.com/directions/
 Example:
 
 directions := NewDirections("Amsterdam,Netherlands", []string{"Antwerp,Belgium"})
-directions.Unit = "k" // switch to km
+directions.Unit = Kilometers // switch to km
+directions.To = append(directions.To, NewLatLngWaypoint(LatLng{Lat: 50.85, Lng: 4.35}))
 
 url := directions.URL("json")
 json := directions.Dump("json")
 xml := directions.Dump("xml")
-distance := directions.Distance("k")
+distance := directions.Distance(Kilometers)
 route := directions.Get().Route
 
 */
@@ -19,42 +20,44 @@ package geocoder
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 )
 
 const (
-	directionURL = "https://open.mapquestapi.com/directions/v2/route?inFormat=kvp&key="
+	directionsBaseURL = "https://open.mapquestapi.com/directions/v2/"
 )
 
 // Directions provide information on how to get from one location
 // to one (or more) other locations together with copyright and statuscode info.
-// (style, shape and mapstate options are not implemented)
+// (style and mapstate options are not implemented)
 type Directions struct {
 	// Starting location
-	From string
-	// Ending location
-	To []string
+	From Waypoint
+	// Ending location(s)
+	To []Waypoint
 	// type of units for calculating distance: m (Miles, default) or k (Km)
-	Unit string
+	Unit Unit
 	// fastest(default), shortest, pedestrian, multimodal, bicycle
-	RouteType string
+	RouteType RouteType
 	// If true(default), reverse geocode call will be performed even on lat/long.
 	DoReverseGeocode bool
 	// none, text(default), html, microformat
-	NarrativeType string
+	NarrativeType NarrativeType
 	// Encompass extra advice such as intersections (default false)
 	EnhancedNarrative bool
 	// The maximum number of Link IDs to return for each maneuver (default is 0)
 	MaxLinkID int
 	// en_US(default), en_GB, fr_CA, fr_FR, de_DE, es_ES, es_MX, ru_RU
-	Locale string
+	Locale Locale
 	// Limited Access, Toll Road, Ferry, Unpaved, Seasonal Closure, Country Crossing
-	Avoids []string
+	Avoids []Avoid
 	// Link IDs of roads to absolutely avoid. May cause some routes to fail.
 	MustAvoidLinkIDs []int
 	// Link IDs of roads to try to avoid during route calculation without guarantee.
@@ -67,18 +70,34 @@ type Directions struct {
 	DestinationManeuverDisplay bool
 	// To return a route shape without a mapState. (default false)
 	FullShape bool
+	// Format of the returned shape points: raw(default), cmp, cmp6
+	ShapeFormat ShapeFormat
 	// A value of < 1 favors cycling on non-bike lane roads. [0.1..1(default)..100]
 	CyclingRoadFactor float64
 	// DEFAULT_STRATEGY (default), AVOID_UP_HILL, AVOID_DOWN_HILL,AVOID_ALL_HILLS,FAVOR_UP_HILL,FAVOR_DOWN_HILL,FAVOR_ALL_HILLS
-	RoadGradeStrategy string
+	RoadGradeStrategy RoadGradeStrategy
 	// cautious, normal, aggressive
-	DrivingStyle string
+	DrivingStyle DrivingStyle
 	// Fuel efficiency, given as miles per gallon. (0..235 mpg)
 	HighwayEfficiency float64
 	// If true (default), a small staticmap is displayed per maneuver
 	ManMaps bool
 	// Walking speed, always in miles per hour independent from unit (default 2.5)
 	WalkingSpeed float64
+	// Maximum number of routes; above 1, alternate routes are requested (default 1)
+	MaxRoutes int
+	// Percentage an alternate route may take longer than the fastest route (default 25)
+	TimeOverage float64
+	// Departure time (in the time zone of the starting location, see TimeZone)
+	DepartureTime time.Time
+	// Arrival time (in the time zone of the destination, see TimeZone)
+	ArrivalTime time.Time
+	// Time zone of DepartureTime or ArrivalTime (nil keeps their own time zone)
+	TimeZone *time.Location
+	// 0 none, 1 current time, 2 departure, 3 arrival (default derived from DepartureTime or ArrivalTime)
+	TimeType TimeType
+	// Use current and historical traffic for the time calculation (default false)
+	UseTraffic bool
 	// Session id
 	SessionID string
 }
@@ -86,31 +105,46 @@ type Directions struct {
 // NewDirections is a constructor to initialize a Directions struct
 // with mapquest defaults.
 func NewDirections(from string, to []string) *Directions {
+	return NewWaypointDirections(NewAddressWaypoint(from), AddressWaypoints(to))
+}
+
+// Waypoints returns all locations of the directions: From followed by To.
+func (directions Directions) Waypoints() []Waypoint {
+	return append([]Waypoint{directions.From}, directions.To...)
+}
+
+// NewWaypointDirections is a constructor to initialize a Directions struct
+// between waypoints with mapquest defaults.
+func NewWaypointDirections(from Waypoint, to []Waypoint) *Directions {
 	return &Directions{
 		From:                       from,
 		To:                         to,
-		Unit:                       "m",
-		RouteType:                  "fastest",
+		Unit:                       Miles,
+		RouteType:                  Fastest,
 		DoReverseGeocode:           true,
-		NarrativeType:              "text",
+		NarrativeType:              NarrativeText,
 		EnhancedNarrative:          false,
 		MaxLinkID:                  0,
-		Locale:                     "en_US",
+		Locale:                     LocaleEnUS,
 		StateBoundaryDisplay:       true,
 		CountryBoundaryDisplay:     true,
 		DestinationManeuverDisplay: true,
 		FullShape:                  false,
+		ShapeFormat:                ShapeRaw,
 		CyclingRoadFactor:          1,
-		RoadGradeStrategy:          "DEFAULT_STRATEGY",
-		DrivingStyle:               "normal",
+		RoadGradeStrategy:          RoadGradeDefault,
+		DrivingStyle:               DrivingNormal,
 		HighwayEfficiency:          22,
 		ManMaps:                    true,
 		WalkingSpeed:               -1, // 2.5
+		MaxRoutes:                  1,
+		TimeOverage:                25,
 		SessionID:                  "",
 	}
 }
 
 // URL constructs the mapquest directions url (format is json or xml)
+// Waypoints are written as single line addresses without their stop options.
 func (directions Directions) URL(format string) string {
 	// http://stackoverflow.com/questions/1760757/how-to-efficiently-concatenate-strings-in-go
 	var (
@@ -129,21 +163,21 @@ func (directions Directions) URL(format string) string {
 			}
 		}
 	}
-	routeURL.WriteString(directionURL)
+	routeURL.WriteString(directionsBaseURL + directions.service() + "?inFormat=kvp&key=")
 	routeURL.WriteString(apiKey)
 	routeURL.WriteString("&outFormat=" + format)
-	routeURL.WriteString("&from=" + url.QueryEscape(directions.From))
+	routeURL.WriteString("&from=" + url.QueryEscape(directions.From.String()))
 	for _, to := range directions.To {
-		routeURL.WriteString("&to=" + url.QueryEscape(to))
+		routeURL.WriteString("&to=" + url.QueryEscape(to.String()))
 	}
-	routeURL.WriteString("&unit=" + directions.Unit)
-	routeURL.WriteString("&routeType" + directions.RouteType)
-	routeURL.WriteString("&narrativeType=" + directions.NarrativeType)
+	routeURL.WriteString("&unit=" + string(directions.Unit))
+	routeURL.WriteString("&routeType=" + string(directions.RouteType))
+	routeURL.WriteString("&narrativeType=" + string(directions.NarrativeType))
 	routeURL.WriteString("&enhancedNarrative=" + strconv.FormatBool(directions.EnhancedNarrative))
 	routeURL.WriteString("&maxLinkId=" + strconv.Itoa(directions.MaxLinkID))
-	routeURL.WriteString("&locale=" + directions.Locale)
+	routeURL.WriteString("&locale=" + string(directions.Locale))
 	for _, avoids := range directions.Avoids {
-		routeURL.WriteString("&avoids=" + url.QueryEscape(avoids))
+		routeURL.WriteString("&avoids=" + url.QueryEscape(string(avoids)))
 	}
 	writeStringInts("&mustAvoidLinkIds=", directions.MustAvoidLinkIDs)
 	writeStringInts("&tryAvoidLinkIds=", directions.TryAvoidLinkIDs)
@@ -151,20 +185,143 @@ func (directions Directions) URL(format string) string {
 	routeURL.WriteString("&countryBoundaryDisplay=" + strconv.FormatBool(directions.CountryBoundaryDisplay))
 	routeURL.WriteString("&destinationManeuverDisplay=" + strconv.FormatBool(directions.DestinationManeuverDisplay))
 	routeURL.WriteString("&fullShape=" + strconv.FormatBool(directions.FullShape))
+	routeURL.WriteString("&shapeFormat=" + string(directions.ShapeFormat))
 	routeURL.WriteString("&cyclingRoadFactor=" + strconv.FormatFloat(directions.CyclingRoadFactor, 'f', -1, 64))
-	routeURL.WriteString("&roadGradeStrategy=" + directions.RoadGradeStrategy)
-	routeURL.WriteString("&drivingStyle=" + directions.DrivingStyle)
+	routeURL.WriteString("&roadGradeStrategy=" + string(directions.RoadGradeStrategy))
+	routeURL.WriteString("&drivingStyle=" + string(directions.DrivingStyle))
 	routeURL.WriteString("&highwayEfficiency=" + strconv.FormatFloat(directions.HighwayEfficiency, 'f', -1, 64))
 	routeURL.WriteString("&manMaps=" + strconv.FormatBool(directions.ManMaps))
 	routeURL.WriteString("&walkingSpeed=" + strconv.FormatFloat(directions.WalkingSpeed, 'f', -1, 64))
+	if directions.MaxRoutes > 1 {
+		routeURL.WriteString("&maxRoutes=" + strconv.Itoa(directions.MaxRoutes))
+		routeURL.WriteString("&timeOverage=" + strconv.FormatFloat(directions.TimeOverage, 'f', -1, 64))
+	}
+	if timeType := directions.timeType(); timeType != TimeNone {
+		routeURL.WriteString("&timeType=" + strconv.Itoa(int(timeType)))
+	}
+	if moment, ok := directions.localTime(); ok {
+		routeURL.WriteString("&dateType=0")
+		routeURL.WriteString("&date=" + url.QueryEscape(moment.Format(directionsDateFormat)))
+		routeURL.WriteString("&localTime=" + url.QueryEscape(moment.Format(directionsTimeFormat)))
+	}
+	if directions.UseTraffic {
+		routeURL.WriteString("&useTraffic=true")
+	}
 	if directions.SessionID != "" {
 		routeURL.WriteString("&sessionId=" + directions.SessionID)
 	}
 	return routeURL.String()
 }
 
-// Dump directions as undecoded json or xml bytes
+// directionsOptions is the json options object of a directions request
+type directionsOptions struct {
+	Unit                       Unit              `json:"unit"`
+	RouteType                  RouteType         `json:"routeType"`
+	DoReverseGeocode           bool              `json:"doReverseGeocode"`
+	NarrativeType              NarrativeType     `json:"narrativeType"`
+	EnhancedNarrative          bool              `json:"enhancedNarrative"`
+	MaxLinkID                  int               `json:"maxLinkId"`
+	Locale                     Locale            `json:"locale"`
+	Avoids                     []Avoid           `json:"avoids,omitempty"`
+	MustAvoidLinkIDs           []int             `json:"mustAvoidLinkIds,omitempty"`
+	TryAvoidLinkIDs            []int             `json:"tryAvoidLinkIds,omitempty"`
+	StateBoundaryDisplay       bool              `json:"stateBoundaryDisplay"`
+	CountryBoundaryDisplay     bool              `json:"countryBoundaryDisplay"`
+	DestinationManeuverDisplay bool              `json:"destinationManeuverDisplay"`
+	FullShape                  bool              `json:"fullShape"`
+	ShapeFormat                ShapeFormat       `json:"shapeFormat"`
+	CyclingRoadFactor          float64           `json:"cyclingRoadFactor"`
+	RoadGradeStrategy          RoadGradeStrategy `json:"roadGradeStrategy"`
+	DrivingStyle               DrivingStyle      `json:"drivingStyle"`
+	HighwayEfficiency          float64           `json:"highwayEfficiency"`
+	ManMaps                    bool              `json:"manMaps"`
+	WalkingSpeed               float64           `json:"walkingSpeed"`
+	MaxRoutes                  int               `json:"maxRoutes,omitempty"`
+	TimeOverage                float64           `json:"timeOverage,omitempty"`
+	TimeType                   TimeType          `json:"timeType,omitempty"`
+	DateType                   *int              `json:"dateType,omitempty"`
+	Date                       string            `json:"date,omitempty"`
+	LocalTime                  string            `json:"localTime,omitempty"`
+	UseTraffic                 bool              `json:"useTraffic,omitempty"`
+	SessionID                  string            `json:"sessionId,omitempty"`
+}
+
+// directionsBody will be marshalled as json to send in body with http post
+type directionsBody struct {
+	// single line addresses (string) or location objects (locationBody)
+	Locations []interface{}     `json:"locations"`
+	Options   directionsOptions `json:"options"`
+}
+
+// body constructs the json body of a directions request
+func (directions Directions) body() directionsBody {
+	locations := []interface{}{directions.From.body()}
+	for _, to := range directions.To {
+		locations = append(locations, to.body())
+	}
+	body := directionsBody{
+		Locations: locations,
+		Options: directionsOptions{
+			Unit:                       directions.Unit,
+			RouteType:                  directions.RouteType,
+			DoReverseGeocode:           directions.DoReverseGeocode,
+			NarrativeType:              directions.NarrativeType,
+			EnhancedNarrative:          directions.EnhancedNarrative,
+			MaxLinkID:                  directions.MaxLinkID,
+			Locale:                     directions.Locale,
+			Avoids:                     directions.Avoids,
+			MustAvoidLinkIDs:           directions.MustAvoidLinkIDs,
+			TryAvoidLinkIDs:            directions.TryAvoidLinkIDs,
+			StateBoundaryDisplay:       directions.StateBoundaryDisplay,
+			CountryBoundaryDisplay:     directions.CountryBoundaryDisplay,
+			DestinationManeuverDisplay: directions.DestinationManeuverDisplay,
+			FullShape:                  directions.FullShape,
+			ShapeFormat:                directions.ShapeFormat,
+			CyclingRoadFactor:          directions.CyclingRoadFactor,
+			RoadGradeStrategy:          directions.RoadGradeStrategy,
+			DrivingStyle:               directions.DrivingStyle,
+			HighwayEfficiency:          directions.HighwayEfficiency,
+			ManMaps:                    directions.ManMaps,
+			WalkingSpeed:               directions.WalkingSpeed,
+			TimeType:                   directions.timeType(),
+			UseTraffic:                 directions.UseTraffic,
+			SessionID:                  directions.SessionID,
+		},
+	}
+	if moment, ok := directions.localTime(); ok {
+		specificDate := 0
+		body.Options.DateType = &specificDate
+		body.Options.Date = moment.Format(directionsDateFormat)
+		body.Options.LocalTime = moment.Format(directionsTimeFormat)
+	}
+	if directions.MaxRoutes > 1 {
+		body.Options.MaxRoutes = directions.MaxRoutes
+		body.Options.TimeOverage = directions.TimeOverage
+	}
+	return body
+}
+
+// post sends the directions as json body to a directions service
+// (eg "route") and decodes the json response into results.
+func (directions Directions) post(service string, results interface{}) error {
+	b, err := json.Marshal(directions.body())
+	if err != nil {
+		return err
+	}
+	resp, err := http.Post(directionsBaseURL+service+"?key="+apiKey, "application/json", bytes.NewBuffer(b))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return decoder(resp).Decode(results)
+}
+
+// Dump directions as undecoded json or xml bytes.
+// Dump uses the GET url (see URL) and is meant for debugging.
 func (directions Directions) Dump(format string) (data []byte, err error) {
+	if err = directions.Validate(); err != nil {
+		return
+	}
 	resp, err := http.Get(directions.URL(format))
 	if err != nil {
 		return
@@ -175,19 +332,16 @@ func (directions Directions) Dump(format string) (data []byte, err error) {
 }
 
 // Distance calculated in km or miles (unit is k [km] or m [miles])
-func (directions Directions) Distance(unit string) (distance float64, err error) {
+func (directions Directions) Distance(unit Unit) (distance float64, err error) {
 	// these changes are made on a copy and as such invisible to caller
 	directions.ManMaps = false
-	directions.NarrativeType = "none"
+	directions.NarrativeType = NarrativeNone
 	directions.Unit = unit
-	resp, err := http.Get(directions.URL("json"))
-	if err != nil {
+	if err = directions.Validate(); err != nil {
 		return
 	}
-	defer resp.Body.Close()
-	// Decode our JSON results
 	results := DistanceResults{}
-	err = decoder(resp).Decode(&results)
+	err = directions.post("route", &results)
 	if err != nil {
 		return
 	}
@@ -207,23 +361,49 @@ type DistanceResults struct {
 	Info Info `json:"info"`
 }
 
-// Get the Direction Results (Route & Info)
+// service returns the directions service which handles the directions
+func (directions Directions) service() string {
+	if directions.MaxRoutes > 1 {
+		return "alternateroutes"
+	}
+	return "route"
+}
+
+// Get the Direction Results (Route & Info).
+// With MaxRoutes above 1, alternate routes are included (see DirectionsResults.Routes).
 func (directions Directions) Get() (results *DirectionsResults, err error) {
-	resp, err := http.Get(directions.URL("json"))
-	if err != nil {
+	return directions.route(directions.service())
+}
+
+// route requests a route from a directions service (eg "route")
+func (directions Directions) route(service string) (results *DirectionsResults, err error) {
+	if err = directions.Validate(); err != nil {
 		return
 	}
-	defer resp.Body.Close()
-	// Decode our JSON results
 	results = &DirectionsResults{}
-	err = decoder(resp).Decode(results)
+	err = directions.post(service, results)
 	if err != nil {
 		return
 	}
+	err = directions.complete(results)
+	return
+}
+
+// complete checks the status of the results and decodes their shapes
+func (directions Directions) complete(results *DirectionsResults) error {
 	if results.Info.Statuscode != 0 {
-		err = results.Info
+		return results.Info
 	}
-	return
+	precision := shapePrecision(directions.ShapeFormat)
+	if err := results.Route.Shape.decompress(precision); err != nil {
+		return err
+	}
+	for i := range results.Route.AlternateRoutes {
+		if err := results.Route.AlternateRoutes[i].Route.Shape.decompress(precision); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // DirectionsResults can be decoded from the Directions route response
@@ -265,13 +445,16 @@ type Route struct {
 	HasUnpaved bool `json:"hasUnpaved"`
 	// Returns true if at least one leg contains a Country Crossing.
 	HasCountryCross bool `json:"hasCountryCross"`
+	// Returns true if at least one leg contains a Bridge.
+	HasBridge bool `json:"hasBridge"`
+	// Returns true if at least one leg contains a Tunnel.
+	HasTunnel bool `json:"hasTunnel"`
 	// Returns lat/lng bounding rectangle of all points
-	BoundingBox struct {
-		UpperLeft  LatLng `json:"ul"`
-		LowerRight LatLng `json:"lr"`
-	} `json:"BoundingBox"`
+	BoundingBox BBox `json:"boundingBox"`
 	// Returns the calculated elapsed time in seconds for the route.
 	Time int `json:"time"`
+	// Returns the elapsed time in seconds with traffic (-1 if not available)
+	RealTime int `json:"realTime"`
 	// Returns the calculated elapsed time as formatted text in HH:MM:SS format.
 	FormattedTime string `json:"formattedTime"`
 	// Returns the calculated distance of the route.
@@ -291,8 +474,48 @@ type Route struct {
 	LocationSequence []int `json:"locationSequence"`
 	// A unique identifier used to refer to a session
 	SessionID string `json:"sessionId"`
-	/* // Routing Options (not necessary as same as request)
-	Options Options `json:"options"` */
+	// Route shape with the shape point indexes of legs and maneuvers
+	Shape Shape `json:"shape"`
+	// Locations added by the route calculation (eg for via points)
+	ComputedWaypoints []Location `json:"computedWaypoints"`
+	// Alternate routes (if requested with MaxRoutes)
+	AlternateRoutes []AlternateRoute `json:"alternateRoutes,omitempty"`
+	// Routing options as applied by mapquest
+	Options RouteOptions `json:"options"`
+}
+
+// RouteOptions echoes the options used to calculate a route. Mapquest
+// returns some of them in another form than requested (eg unit "M",
+// routeType "FASTEST" and drivingStyle 2 for normal).
+type RouteOptions struct {
+	Unit                       string  `json:"unit"`
+	RouteType                  string  `json:"routeType"`
+	DoReverseGeocode           bool    `json:"doReverseGeocode"`
+	NarrativeType              string  `json:"narrativeType"`
+	EnhancedNarrative          bool    `json:"enhancedNarrative"`
+	MaxLinkID                  int     `json:"maxLinkId"`
+	Locale                     string  `json:"locale"`
+	MustAvoidLinkIDs           []int   `json:"mustAvoidLinkIds"`
+	TryAvoidLinkIDs            []int   `json:"tryAvoidLinkIds"`
+	StateBoundaryDisplay       bool    `json:"stateBoundaryDisplay"`
+	CountryBoundaryDisplay     bool    `json:"countryBoundaryDisplay"`
+	SideOfStreetDisplay        bool    `json:"sideOfStreetDisplay"`
+	DestinationManeuverDisplay bool    `json:"destinationManeuverDisplay"`
+	FullShape                  bool    `json:"fullShape"`
+	ShapeFormat                string  `json:"shapeFormat"`
+	Generalize                 float64 `json:"generalize"`
+	CyclingRoadFactor          float64 `json:"cyclingRoadFactor"`
+	DrivingStyle               int     `json:"drivingStyle"`
+	HighwayEfficiency          float64 `json:"highwayEfficiency"`
+	WalkingSpeed               float64 `json:"walkingSpeed"`
+	MaxWalkingDistance         float64 `json:"maxWalkingDistance"`
+	TransferPenalty            float64 `json:"transferPenalty"`
+	UrbanAvoidFactor           float64 `json:"urbanAvoidFactor"`
+	FilterZoneFactor           float64 `json:"filterZoneFactor"`
+	AvoidTimedConditions       bool    `json:"avoidTimedConditions"`
+	TimeType                   int     `json:"timeType"`
+	UseTraffic                 bool    `json:"useTraffic"`
+	RouteNumber                int     `json:"routeNumber"`
 }
 
 // Leg contains the maneuvers describing how to get from one location
@@ -310,6 +533,10 @@ type Leg struct {
 	HasUnpaved bool `json:"hasUnpaved"`
 	// Returns true if at least one maneuver contains a Country Crossing.
 	HasCountryCross bool `json:"hasCountryCross"`
+	// Returns true if at least one maneuver contains a Bridge.
+	HasBridge bool `json:"hasBridge"`
+	// Returns true if at least one maneuver contains a Tunnel.
+	HasTunnel bool `json:"hasTunnel"`
 	// Returns the calculated elapsed time in seconds for the leg.
 	Time int `json:"time"`
 	// Returns the calculated elapsed time as formatted text in HH:MM:SS format.
@@ -331,7 +558,6 @@ type Leg struct {
 	DestIndex int `json:"destIndex"`
 	// The rephrased destination narrative string for the destination maneuver.
 	DestNarrative string `json:"destNarrative"`
-	/* RoadGradeStrategy ??? `json:"roadGradeStrategy"` */
 }
 
 // Maneuver describes each one step in a route narrative.
@@ -350,19 +576,19 @@ type Maneuver struct {
 	MapURL string `json:"mapUrl"`
 	// Textual driving directions for a particular maneuver.
 	Narrative string `json:"narrative"`
-	/* // A collection of maneuverNote objects, one for each maneuver.
-	   ManeuverNotes []ManeuverNote `json:"maneuverNotes"` */
+	// A collection of maneuverNote objects, one for each restriction on the maneuver.
+	ManeuverNotes []ManeuverNote `json:"maneuverNotes"`
 	// none=0,north=1,northwest=2,northeast=3,south=4,southeast=5,southwest=6,west=7,east=8
-	Direction int `json:"direction"`
+	Direction Compass `json:"direction"`
 	// Name of the direction
 	DirectionName string `json:"directionName"`
 	// Collection of street names this maneuver applies to
 	Streets []string `json:"streets"`
 	// none=0,portions toll=1,portions unpaved=2,possible seasonal road closure=4,gate=8,ferry=16,avoid id=32,country crossing=64,limited access (highways)=128
-	Attributes int `json:"attributes"`
+	Attributes ManeuverAttribute `json:"attributes"`
 	// straight=0,slight right=1,right=2,sharp right=3,reverse=4,sharp left=5,left=6,slight left=7,right u-turn=8,
 	// left u-turn=9,right merge=10,left merge=11,right on ramp=12,left on ramp=13,right off ramp=14,left off ramp=15,right fork=16,left fork=17,straight fork=18
-	TurnType int `json:"direction"`
+	TurnType TurnType `json:"turnType"`
 	// 1st shape point latLng for a particular maneuver (eg for zooming)
 	StartPoint LatLng `json:"startPoint"`
 	// Icon
@@ -375,19 +601,79 @@ type Maneuver struct {
 
 // Sign specifies information for a particular maneuver.
 type Sign struct {
-	Text      string `json:"text"`
-	ExtraText string `json:"extraText"`
-	Direction int    `json:"direction"`
+	Text      string  `json:"text"`
+	ExtraText string  `json:"extraText"`
+	Direction Compass `json:"direction"`
 	// road shield
 	Type int `json:"type"`
 	// Image
 	URL string `json:"url"`
 }
 
-/* TODO
-// Maneuver notes can exist for Timed Turn Restrictions, Timed Access Roads, HOV Roads, Seasonal Closures, and Timed Direction of Travel.
+// ManeuverNoteType tells which kind of restriction a maneuver note describes
+type ManeuverNoteType int
+
+// Maneuver note types
+const (
+	NoteTimedTurnRestriction   ManeuverNoteType = 1
+	NoteTimedAccessRoad        ManeuverNoteType = 2
+	NoteHOVRoad                ManeuverNoteType = 3
+	NoteSeasonalClosure        ManeuverNoteType = 4
+	NoteTimedDirectionOfTravel ManeuverNoteType = 5
+)
+
+var maneuverNoteTypeNames = map[ManeuverNoteType]string{
+	NoteTimedTurnRestriction:   "Timed Turn Restriction",
+	NoteTimedAccessRoad:        "Timed Access Road",
+	NoteHOVRoad:                "HOV Road",
+	NoteSeasonalClosure:        "Seasonal Closure",
+	NoteTimedDirectionOfTravel: "Timed Direction of Travel",
+}
+
+func (noteType ManeuverNoteType) String() string {
+	if name, ok := maneuverNoteTypeNames[noteType]; ok {
+		return name
+	}
+	return fmt.Sprintf("Maneuver Note %d", int(noteType))
+}
+
+// Restrictive reports whether the note restricts the use of the road
+func (noteType ManeuverNoteType) Restrictive() bool {
+	_, ok := maneuverNoteTypeNames[noteType]
+	return ok
+}
+
+// ManeuverNote can exist for Timed Turn Restrictions, Timed Access Roads,
+// HOV Roads, Seasonal Closures, and Timed Direction of Travel.
 type ManeuverNote struct {
-	RuleId int `json:"ruleId"`
-	// ... incomplete
+	// Identifier of the rule which causes the note
+	RuleID int `json:"ruleId"`
+	// Kind of restriction
+	Type ManeuverNoteType `json:"manNoteType"`
+	// Text of the note, eg "Closed Nov 1 - Apr 30"
+	Text string `json:"manNoteText"`
+}
+
+// RouteNote is a maneuver note together with the (zero based)
+// leg and maneuver indexes it belongs to.
+type RouteNote struct {
+	Leg      int
+	Maneuver int
+	Note     ManeuverNote
+}
+
+// RestrictiveNotes lists the restrictive maneuver notes of all maneuvers
+// (in route order) so drivers can be warned before departure.
+func (route Route) RestrictiveNotes() []RouteNote {
+	var notes []RouteNote
+	for i, leg := range route.Legs {
+		for j, maneuver := range leg.Maneuvers {
+			for _, note := range maneuver.ManeuverNotes {
+				if note.Type.Restrictive() {
+					notes = append(notes, RouteNote{Leg: i, Maneuver: j, Note: note})
+				}
+			}
+		}
+	}
+	return notes
 }
-*/
@@ -1,7 +1,9 @@
 package geocoder
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"math"
 	"strings"
 	"testing"
@@ -14,8 +16,8 @@ const (
 	testManeuvers  = 52
 	testStatuscode = 0
 	testTime       = 6085
-	testUnit       = "m"
-	testURL        = "https://open.mapquestapi.com/directions/v2/route?inFormat=kvp&key=Fmjtd%7Cluub256alu%2C7s%3Do5-9u82ur&outFormat=json&from=Amsterdam%2CNetherlands&to=Antwerp%2CBelgium&unit=m&routeTypefastest&narrativeType=text&enhancedNarrative=false&maxLinkId=0&locale=en_US&avoids=Ferry&mustAvoidLinkIds=5,7&stateBoundaryDisplay=true&countryBoundaryDisplay=true&destinationManeuverDisplay=true&fullShape=false&cyclingRoadFactor=1&roadGradeStrategy=DEFAULT_STRATEGY&drivingStyle=normal&highwayEfficiency=22&manMaps=true&walkingSpeed=-1"
+	testUnit       = Miles
+	testURL        = "https://open.mapquestapi.com/directions/v2/route?inFormat=kvp&key=Fmjtd%7Cluub256alu%2C7s%3Do5-9u82ur&outFormat=json&from=Amsterdam%2CNetherlands&to=Antwerp%2CBelgium&unit=m&routeType=fastest&narrativeType=text&enhancedNarrative=false&maxLinkId=0&locale=en_US&avoids=Ferry&mustAvoidLinkIds=5,7&stateBoundaryDisplay=true&countryBoundaryDisplay=true&destinationManeuverDisplay=true&fullShape=false&shapeFormat=raw&cyclingRoadFactor=1&roadGradeStrategy=DEFAULT_STRATEGY&drivingStyle=normal&highwayEfficiency=22&manMaps=true&walkingSpeed=-1"
 )
 
 func unexpected(err error, t *testing.T) bool {
@@ -29,7 +31,7 @@ func unexpected(err error, t *testing.T) bool {
 func TestUrl(t *testing.T) {
 	directions := NewDirections("Amsterdam,Netherlands", []string{"Antwerp,Belgium"})
 	directions.MustAvoidLinkIDs = []int{5, 7}
-	directions.Avoids = []string{"Ferry"}
+	directions.Avoids = []Avoid{AvoidFerry}
 	routeURL := directions.URL("json")
 	if testURL != routeURL {
 		t.Errorf("Expected %s ~ Received %s", testURL, routeURL)
@@ -46,7 +48,7 @@ func TestDistance(t *testing.T) {
 		t.Errorf("Expected %d ~ Received %d", testDistance, int(distance))
 	}
 	// distance function may not alter the original unit
-	if directions.Unit != "m" {
+	if directions.Unit != Miles {
 		t.Errorf("Unit: Expected %s ~ Received %s", testUnit, directions.Unit)
 	}
 }
@@ -61,7 +63,7 @@ func TestDistanceError(t *testing.T) {
 
 func TestDirections(t *testing.T) {
 	directions := NewDirections("Amsterdam,Netherlands", []string{"Antwerp,Belgium"})
-	directions.Unit = "k" // switch to km
+	directions.Unit = Kilometers // switch to km
 	directions.ManMaps = false
 	//fmt.Println(string(directions.Dump("json")))
 	results, err := directions.Get()
@@ -99,7 +101,7 @@ func TestDirections(t *testing.T) {
 
 func TestDump(t *testing.T) {
 	directions := NewDirections("1 Coronation Road, London, United Kingdom", []string{"3 Coronation Road, London, United Kingdom"})
-	directions.NarrativeType = "none"
+	directions.NarrativeType = NarrativeNone
 	directions.ManMaps = false
 	results, err := directions.Get()
 	if unexpected(err, t) {
@@ -118,3 +120,112 @@ func TestDump(t *testing.T) {
 		t.Errorf("Expected\n%s\nReceived\n%s", testDump, dump)
 	}
 }
+
+func TestBody(t *testing.T) {
+	via := NewLocationWaypoint(Location{Street: "Grote Markt 1", City: "Antwerpen", CountryCode: "BE"})
+	via.Type = Via
+	directions := NewWaypointDirections(
+		NewLatLngWaypoint(LatLng{Lat: 52.370216, Lng: 4.895168}),
+		[]Waypoint{via, NewAddressWaypoint("Brussels,Belgium")},
+	)
+	directions.Avoids = []Avoid{AvoidFerry}
+	if err := directions.Validate(); unexpected(err, t) {
+		return
+	}
+	body, err := json.Marshal(directions.body())
+	if unexpected(err, t) {
+		return
+	}
+	for _, expected := range []string{
+		`"locations":[{"latLng":{"lat":52.370216,"lng":4.895168}},{"street":"Grote Markt 1","adminArea5":"Antwerpen","adminArea1":"BE","type":"v"},"Brussels,Belgium"]`,
+		`"unit":"m","routeType":"fastest"`,
+		`"avoids":["Ferry"]`,
+	} {
+		if !strings.Contains(string(body), expected) {
+			t.Errorf("Expected body to contain %s ~ Received %s", expected, body)
+		}
+	}
+	routeURL := directions.URL("json")
+	if !strings.Contains(routeURL, "&from=52.370216%2C4.895168&to=Grote+Markt+1%2C+Antwerpen%2C+BE&to=Brussels%2CBelgium&") {
+		t.Errorf("Expected single line locations in %s", routeURL)
+	}
+}
+
+func TestRestrictiveNotes(t *testing.T) {
+	var route Route
+	err := json.Unmarshal([]byte(`{"legs":[{"maneuvers":[{"maneuverNotes":[]},
+{"maneuverNotes":[{"ruleId":12,"manNoteType":4,"manNoteText":"Closed Nov 1 - Apr 30"},{"ruleId":13,"manNoteType":0}]}]},
+{"maneuvers":[{"maneuverNotes":[{"ruleId":14,"manNoteType":1,"manNoteText":"No left turn 7-9AM"}]}]}]}`), &route)
+	if unexpected(err, t) {
+		return
+	}
+	notes := route.RestrictiveNotes()
+	if len(notes) != 2 {
+		t.Fatalf("Expected 2 notes ~ Received %v", notes)
+	}
+	if notes[0].Leg != 0 || notes[0].Maneuver != 1 || notes[0].Note.Type != NoteSeasonalClosure || notes[0].Note.Text != "Closed Nov 1 - Apr 30" {
+		t.Errorf("Unexpected first note %+v", notes[0])
+	}
+	if notes[1].Leg != 1 || notes[1].Note.RuleID != 14 || notes[1].Note.Type.String() != "Timed Turn Restriction" {
+		t.Errorf("Unexpected second note %+v", notes[1])
+	}
+}
+
+// contains reports the path of the first value in expected which
+// is missing or different in received (json decoded values).
+func contains(expected, received interface{}, path string) string {
+	switch expected := expected.(type) {
+	case map[string]interface{}:
+		received, ok := received.(map[string]interface{})
+		if !ok {
+			return path
+		}
+		for key, value := range expected {
+			if missing := contains(value, received[key], path+"."+key); missing != "" {
+				return missing
+			}
+		}
+	case []interface{}:
+		received, ok := received.([]interface{})
+		if !ok || len(received) != len(expected) {
+			return path
+		}
+		for i, value := range expected {
+			if missing := contains(value, received[i], fmt.Sprintf("%s[%d]", path, i)); missing != "" {
+				return missing
+			}
+		}
+	default:
+		if expected != received {
+			return path
+		}
+	}
+	return ""
+}
+
+func TestRouteRoundTrip(t *testing.T) {
+	fixture, err := ioutil.ReadFile("testdata/route.json")
+	if unexpected(err, t) {
+		return
+	}
+	var results DirectionsResults
+	if err = json.Unmarshal(fixture, &results); unexpected(err, t) {
+		return
+	}
+	route := results.Route
+	if route.RealTime != -1 || route.Options.RouteType != "FASTEST" || route.Legs[0].Maneuvers[0].TurnType != 0 ||
+		route.Legs[0].Maneuvers[1].TurnType != -1 || route.Legs[0].Maneuvers[0].Direction != 7 ||
+		route.BoundingBox.UpperLeft.Lng != -0.27602 || route.Locations[1].SideOfStreet != "L" {
+		t.Errorf("Unexpected route %+v", route)
+	}
+	encoded, err := json.Marshal(results)
+	if unexpected(err, t) {
+		return
+	}
+	var expected, received interface{}
+	json.Unmarshal(fixture, &expected)
+	json.Unmarshal(encoded, &received)
+	if missing := contains(expected, received, ""); missing != "" {
+		t.Errorf("Round trip lost %s", missing)
+	}
+}
@@ -0,0 +1,339 @@
+/* Exports directions and geocoding results as GeoJSON, GPX or KML.
+
+Example:
+
+results, err := directions.Get()
+geojson, err := results.GeoJSON()
+gpx, err := results.GPX()
+kml, err := results.KML()
+
+*/
+
+package geocoder
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strconv"
+)
+
+const exportCreator = "github.com/jasonwinn/geocoder"
+
+// FeatureCollection is a GeoJSON feature collection (RFC 7946)
+type FeatureCollection struct {
+	Type     string    `json:"type"`
+	Features []Feature `json:"features"`
+}
+
+// Feature is a GeoJSON feature
+type Feature struct {
+	Type       string                 `json:"type"`
+	Geometry   Geometry               `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// Geometry is a GeoJSON geometry. Coordinates are [lng, lat] positions:
+// a single position for a Point, a slice for a LineString
+// and a slice of rings for a Polygon.
+type Geometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+// position converts a point into a GeoJSON [lng, lat] position
+func position(point LatLng) []float64 {
+	return []float64{point.Lng, point.Lat}
+}
+
+// positions converts points into GeoJSON [lng, lat] positions
+func positions(points []LatLng) [][]float64 {
+	coordinates := make([][]float64, len(points))
+	for i, point := range points {
+		coordinates[i] = position(point)
+	}
+	return coordinates
+}
+
+// NewPointFeature creates a GeoJSON Point feature
+func NewPointFeature(point LatLng, properties map[string]interface{}) Feature {
+	return Feature{
+		Type:       "Feature",
+		Geometry:   Geometry{Type: "Point", Coordinates: position(point)},
+		Properties: properties,
+	}
+}
+
+// NewLineStringFeature creates a GeoJSON LineString feature
+func NewLineStringFeature(points []LatLng, properties map[string]interface{}) Feature {
+	return Feature{
+		Type:       "Feature",
+		Geometry:   Geometry{Type: "LineString", Coordinates: positions(points)},
+		Properties: properties,
+	}
+}
+
+// NewPolygonFeature creates a GeoJSON Polygon feature of a single closed ring
+func NewPolygonFeature(ring []LatLng, properties map[string]interface{}) Feature {
+	return Feature{
+		Type:       "Feature",
+		Geometry:   Geometry{Type: "Polygon", Coordinates: [][][]float64{positions(ring)}},
+		Properties: properties,
+	}
+}
+
+// exportPlace is a named point (maneuver or geocoded location)
+type exportPlace struct {
+	Name       string
+	Point      LatLng
+	Properties map[string]interface{}
+}
+
+// export is the format independent content of an export:
+// a track with one segment per leg and the places along it.
+type export struct {
+	Segments [][]LatLng
+	Places   []exportPlace
+}
+
+// export collects the route shape and maneuver start points.
+// Without a shape (see Directions.FullShape) the maneuver
+// start points are used as the track.
+func (results DirectionsResults) export() export {
+	var content export
+	route := results.Route
+	for i, leg := range route.Legs {
+		segment := route.LegShape(i)
+		for j, maneuver := range leg.Maneuvers {
+			content.Places = append(content.Places, exportPlace{
+				Name:  maneuver.Narrative,
+				Point: maneuver.StartPoint,
+				Properties: map[string]interface{}{
+					"leg":           i,
+					"maneuver":      j,
+					"narrative":     maneuver.Narrative,
+					"streets":       maneuver.Streets,
+					"distance":      maneuver.Distance,
+					"time":          maneuver.Time,
+					"directionName": maneuver.DirectionName,
+				},
+			})
+			if len(route.Shape.Points) == 0 {
+				segment = append(segment, maneuver.StartPoint)
+			}
+		}
+		if len(segment) > 0 {
+			content.Segments = append(content.Segments, segment)
+		}
+	}
+	return content
+}
+
+// export collects the locations of all results.
+func (result GeocodingResult) export() export {
+	var content export
+	for _, r := range result.Results {
+		for _, location := range r.Locations {
+			content.Places = append(content.Places, exportPlace{
+				Name:  r.ProvidedLocation.Location,
+				Point: location.LatLng,
+				Properties: map[string]interface{}{
+					"query":          r.ProvidedLocation.Location,
+					"street":         location.Street,
+					"city":           location.AdminArea5,
+					"county":         location.AdminArea4,
+					"state":          location.AdminArea3,
+					"postalCode":     location.PostalCode,
+					"countryCode":    location.AdminArea1,
+					"geocodeQuality": location.GeocodeQuality,
+				},
+			})
+		}
+	}
+	return content
+}
+
+// featureCollection converts an export into a GeoJSON feature collection
+func (content export) featureCollection() FeatureCollection {
+	collection := FeatureCollection{Type: "FeatureCollection", Features: []Feature{}}
+	var line []LatLng
+	for _, segment := range content.Segments {
+		// consecutive legs share their boundary point
+		if len(line) > 0 && len(segment) > 0 && line[len(line)-1] == segment[0] {
+			segment = segment[1:]
+		}
+		line = append(line, segment...)
+	}
+	if len(line) > 0 {
+		collection.Features = append(collection.Features,
+			NewLineStringFeature(line, map[string]interface{}{"name": "route"}))
+	}
+	for _, place := range content.Places {
+		collection.Features = append(collection.Features, NewPointFeature(place.Point, place.Properties))
+	}
+	return collection
+}
+
+// gpx types (GPX 1.1)
+type (
+	gpxDocument struct {
+		XMLName   xml.Name   `xml:"gpx"`
+		Version   string     `xml:"version,attr"`
+		Creator   string     `xml:"creator,attr"`
+		Xmlns     string     `xml:"xmlns,attr"`
+		Waypoints []gpxPoint `xml:"wpt"`
+		Tracks    []gpxTrack `xml:"trk"`
+	}
+	gpxTrack struct {
+		Name     string       `xml:"name"`
+		Segments []gpxSegment `xml:"trkseg"`
+	}
+	gpxSegment struct {
+		Points []gpxPoint `xml:"trkpt"`
+	}
+	gpxPoint struct {
+		Lat  float64 `xml:"lat,attr"`
+		Lng  float64 `xml:"lon,attr"`
+		Name string  `xml:"name,omitempty"`
+	}
+)
+
+// gpx converts an export into GPX waypoints and a track
+func (content export) gpx() ([]byte, error) {
+	document := gpxDocument{
+		Version: "1.1",
+		Creator: exportCreator,
+		Xmlns:   "http://www.topografix.com/GPX/1/1",
+	}
+	for _, place := range content.Places {
+		document.Waypoints = append(document.Waypoints, gpxPoint{Lat: place.Point.Lat, Lng: place.Point.Lng, Name: place.Name})
+	}
+	if len(content.Segments) > 0 {
+		track := gpxTrack{Name: "route"}
+		for _, segment := range content.Segments {
+			var trackSegment gpxSegment
+			for _, point := range segment {
+				trackSegment.Points = append(trackSegment.Points, gpxPoint{Lat: point.Lat, Lng: point.Lng})
+			}
+			track.Segments = append(track.Segments, trackSegment)
+		}
+		document.Tracks = []gpxTrack{track}
+	}
+	return marshalXML(document)
+}
+
+// kml types (KML 2.2)
+type (
+	kmlDocument struct {
+		XMLName    xml.Name       `xml:"kml"`
+		Xmlns      string         `xml:"xmlns,attr"`
+		Name       string         `xml:"Document>name"`
+		Placemarks []kmlPlacemark `xml:"Document>Placemark"`
+	}
+	kmlPlacemark struct {
+		Name       string            `xml:"name"`
+		Point      *kmlGeometry      `xml:"Point,omitempty"`
+		LineString *kmlGeometry      `xml:"LineString,omitempty"`
+		Geometries *kmlMultiGeometry `xml:"MultiGeometry,omitempty"`
+	}
+	kmlMultiGeometry struct {
+		LineStrings []kmlGeometry `xml:"LineString"`
+	}
+	kmlGeometry struct {
+		Coordinates string `xml:"coordinates"`
+	}
+)
+
+// kmlCoordinates formats points as a KML coordinate tuple list (lng,lat)
+func kmlCoordinates(points ...LatLng) string {
+	var coordinates bytes.Buffer
+	for i, point := range points {
+		if i > 0 {
+			coordinates.WriteString(" ")
+		}
+		coordinates.WriteString(strconv.FormatFloat(point.Lng, 'f', -1, 64))
+		coordinates.WriteString(",")
+		coordinates.WriteString(strconv.FormatFloat(point.Lat, 'f', -1, 64))
+	}
+	return coordinates.String()
+}
+
+// kml converts an export into KML placemarks
+func (content export) kml() ([]byte, error) {
+	document := kmlDocument{
+		Xmlns: "http://www.opengis.net/kml/2.2",
+		Name:  exportCreator,
+	}
+	switch len(content.Segments) {
+	case 0:
+	case 1:
+		document.Placemarks = append(document.Placemarks, kmlPlacemark{
+			Name:       "route",
+			LineString: &kmlGeometry{Coordinates: kmlCoordinates(content.Segments[0]...)},
+		})
+	default:
+		var geometries kmlMultiGeometry
+		for _, segment := range content.Segments {
+			geometries.LineStrings = append(geometries.LineStrings, kmlGeometry{Coordinates: kmlCoordinates(segment...)})
+		}
+		document.Placemarks = append(document.Placemarks, kmlPlacemark{Name: "route", Geometries: &geometries})
+	}
+	for _, place := range content.Places {
+		document.Placemarks = append(document.Placemarks, kmlPlacemark{
+			Name:  place.Name,
+			Point: &kmlGeometry{Coordinates: kmlCoordinates(place.Point)},
+		})
+	}
+	return marshalXML(document)
+}
+
+// marshalXML marshals an indented xml document including the xml header
+func marshalXML(document interface{}) ([]byte, error) {
+	data, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// FeatureCollection returns the route as a GeoJSON LineString
+// together with a Point for every maneuver.
+func (results DirectionsResults) FeatureCollection() FeatureCollection {
+	return results.export().featureCollection()
+}
+
+// GeoJSON encodes the route as a GeoJSON FeatureCollection
+func (results DirectionsResults) GeoJSON() ([]byte, error) {
+	return json.Marshal(results.FeatureCollection())
+}
+
+// GPX encodes the route as a GPX track (a segment per leg)
+// together with a waypoint for every maneuver.
+func (results DirectionsResults) GPX() ([]byte, error) {
+	return results.export().gpx()
+}
+
+// KML encodes the route and maneuvers as KML placemarks
+func (results DirectionsResults) KML() ([]byte, error) {
+	return results.export().kml()
+}
+
+// FeatureCollection returns a GeoJSON Point for every location.
+func (result GeocodingResult) FeatureCollection() FeatureCollection {
+	return result.export().featureCollection()
+}
+
+// GeoJSON encodes the locations as a GeoJSON FeatureCollection
+func (result GeocodingResult) GeoJSON() ([]byte, error) {
+	return json.Marshal(result.FeatureCollection())
+}
+
+// GPX encodes the locations as GPX waypoints
+func (result GeocodingResult) GPX() ([]byte, error) {
+	return result.export().gpx()
+}
+
+// KML encodes the locations as KML placemarks
+func (result GeocodingResult) KML() ([]byte, error) {
+	return result.export().kml()
+}
@@ -0,0 +1,92 @@
+package geocoder
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const testExportRoute = `{"route":{"legs":[{"maneuvers":[
+{"narrative":"Start out going west on Coronation Road.","startPoint":{"lat":51.529315,"lng":-0.269962},"streets":["Coronation Road"]},
+{"narrative":"Welcome to 3 Coronation Road.","startPoint":{"lat":51.528568,"lng":-0.274354}}]}],
+"shape":` + testShapeJSON + `}}`
+
+func testExportResults(t *testing.T) *DirectionsResults {
+	results := &DirectionsResults{}
+	if err := json.Unmarshal([]byte(testExportRoute), results); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return results
+}
+
+func TestGeoJSON(t *testing.T) {
+	collection := testExportResults(t).FeatureCollection()
+	if len(collection.Features) != 3 {
+		t.Fatalf("Expected 3 features ~ Received %d", len(collection.Features))
+	}
+	line := collection.Features[0].Geometry
+	if line.Type != "LineString" || len(line.Coordinates.([][]float64)) != 5 {
+		t.Errorf("Expected LineString of 5 positions ~ Received %v", line)
+	}
+	point := collection.Features[1]
+	coordinates := point.Geometry.Coordinates.([]float64)
+	if coordinates[0] != -0.269962 || coordinates[1] != 51.529315 {
+		t.Errorf("Expected [lng, lat] position ~ Received %v", coordinates)
+	}
+	if point.Properties["narrative"] != "Start out going west on Coronation Road." {
+		t.Errorf("Narrative property: Received %v", point.Properties["narrative"])
+	}
+	if _, err := testExportResults(t).GeoJSON(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestGPX(t *testing.T) {
+	data, err := testExportResults(t).GPX()
+	if unexpected(err, t) {
+		return
+	}
+	gpx := string(data)
+	for _, expected := range []string{
+		`<gpx version="1.1"`,
+		`<wpt lat="51.529315" lon="-0.269962">`,
+		`<name>Welcome to 3 Coronation Road.</name>`,
+		`<trkpt lat="51.528568" lon="-0.274354"></trkpt>`,
+	} {
+		if !strings.Contains(gpx, expected) {
+			t.Errorf("Expected GPX to contain %s ~ Received\n%s", expected, gpx)
+		}
+	}
+	if n := strings.Count(gpx, "<trkpt"); n != 5 {
+		t.Errorf("Expected 5 track points ~ Received %d", n)
+	}
+}
+
+func TestKML(t *testing.T) {
+	data, err := testExportResults(t).KML()
+	if unexpected(err, t) {
+		return
+	}
+	kml := string(data)
+	for _, expected := range []string{
+		`<LineString>`,
+		`<coordinates>-0.269962,51.529315 -0.271016,51.529087`,
+		`<Point>`,
+	} {
+		if !strings.Contains(kml, expected) {
+			t.Errorf("Expected KML to contain %s ~ Received\n%s", expected, kml)
+		}
+	}
+}
+
+func TestGeocodingGeoJSON(t *testing.T) {
+	var result GeocodingResult
+	err := json.Unmarshal([]byte(`{"results":[{"providedLocation":{"location":"Seattle WA"},"locations":[{"adminArea5":"Seattle","latLng":{"lat":47.603832,"lng":-122.330062}}]}]}`), &result)
+	if unexpected(err, t) {
+		return
+	}
+	collection := result.FeatureCollection()
+	if len(collection.Features) != 1 || collection.Features[0].Properties["city"] != "Seattle" {
+		t.Errorf("Expected a single Seattle feature ~ Received %v", collection.Features)
+	}
+}
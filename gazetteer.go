@@ -0,0 +1,369 @@
+/* Offline reverse geocoding from a local gazetteer.
+
+A Gazetteer answers reverse lookups without a network: the nearest place of a
+GeoNames cities file (eg cities15000.txt from https://download.geonames.org/export/dump/)
+gives the city, admin boundary polygons (GeoJSON) give the areas containing
+the point. The GeoNames admin codes of a place resolve to names with the
+admin1CodesASCII.txt and admin2Codes.txt files. It implements ReverseGeocoder,
+like the online MapquestReverseGeocoder.
+
+Example:
+
+gazetteer := NewGazetteer()
+err := gazetteer.LoadCities(citiesFile)
+err = gazetteer.LoadAdminCodes(admin1CodesFile)
+err = gazetteer.LoadAdminAreas(statesFile)
+location, err := gazetteer.ReverseGeocode(LatLng{Lat: 47.6062, Lng: -122.3321})
+location.City // Seattle
+
+*/
+
+package geocoder
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrNoPlace is returned when a gazetteer knows no place at a point
+var ErrNoPlace = errors.New("No place found at the point")
+
+// Place is a populated place of a gazetteer
+type Place struct {
+	// GeoNames id
+	ID          string
+	Name        string
+	LatLng      LatLng
+	CountryCode string
+	// GeoNames admin1 (state) and admin2 (county) codes, eg "WA" and "033"
+	Admin1Code, Admin2Code string
+	// State and county names, empty unless known
+	State, County string
+	Population    int
+}
+
+// location converts the place into a city location, names missing state
+// and county names from the admin codes.
+func (place Place) location(adminNames map[string]string) Location {
+	location := Location{City: place.Name, CityType: "City", State: place.State, County: place.County, CountryCode: place.CountryCode}
+	if location.State == "" && place.Admin1Code != "" {
+		location.State = adminNames[place.CountryCode+"."+place.Admin1Code]
+	}
+	if location.County == "" && place.Admin2Code != "" {
+		location.County = adminNames[place.CountryCode+"."+place.Admin1Code+"."+place.Admin2Code]
+	}
+	if location.State != "" {
+		location.StateType = "State"
+	}
+	if location.County != "" {
+		location.CountyType = "County"
+	}
+	if location.CountryCode != "" {
+		location.CountryType = "Country"
+	}
+	return location
+}
+
+// adminArea is an admin boundary of one or more polygons (outer ring and holes)
+type adminArea struct {
+	location Location
+	polygons [][][]LatLng
+	bboxes   []BBox
+}
+
+// contains reports whether the point lies within one of the polygons
+func (area adminArea) contains(point LatLng) bool {
+	for i, polygon := range area.polygons {
+		if !area.bboxes[i].Contains(point) || !NewPolygonGeofence("", polygon[0]).Contains(point) {
+			continue
+		}
+		inHole := false
+		for _, hole := range polygon[1:] {
+			inHole = inHole || NewPolygonGeofence("", hole).Contains(point)
+		}
+		if !inHole {
+			return true
+		}
+	}
+	return false
+}
+
+// Gazetteer is an offline ReverseGeocoder, safe for concurrent use.
+// The zero value is an empty gazetteer without a MaxDistance.
+type Gazetteer struct {
+	// Maximum distance in meters of the nearest place (default 50000, 0: no limit)
+	MaxDistance float64
+	mutex       sync.RWMutex
+	places      []Place
+	// nil until the first lookup after places were added
+	index      *PointIndex
+	areas      []adminArea
+	adminNames map[string]string
+}
+
+// NewGazetteer is a constructor to initialize an empty Gazetteer
+func NewGazetteer() *Gazetteer {
+	return &Gazetteer{MaxDistance: 50000}
+}
+
+// AddPlaces adds places to the gazetteer. The index of the places is
+// rebuilt once at the next lookup, not on every call.
+func (gazetteer *Gazetteer) AddPlaces(places ...Place) {
+	gazetteer.mutex.Lock()
+	defer gazetteer.mutex.Unlock()
+	gazetteer.places = append(gazetteer.places, places...)
+	gazetteer.index = nil
+}
+
+// placeIndex returns the places and their index, building the index if
+// places were added since the last lookup. The index is nil without places.
+func (gazetteer *Gazetteer) placeIndex() ([]Place, *PointIndex) {
+	gazetteer.mutex.RLock()
+	places, index := gazetteer.places, gazetteer.index
+	gazetteer.mutex.RUnlock()
+	if index != nil || len(places) == 0 {
+		return places, index
+	}
+	gazetteer.mutex.Lock()
+	defer gazetteer.mutex.Unlock()
+	if gazetteer.index == nil {
+		points := make([]LatLng, len(gazetteer.places))
+		for i, place := range gazetteer.places {
+			points[i] = place.LatLng
+		}
+		gazetteer.index = NewPointIndex(points, Kilometers)
+	}
+	return gazetteer.places, gazetteer.index
+}
+
+// LoadCities adds the places of a GeoNames cities file: tab separated
+// geonameid, name, asciiname, alternatenames, latitude, longitude, feature
+// class, feature code, country code, cc2, admin1 code, admin2 code, admin3
+// code, admin4 code, population, ... Lines starting with # are skipped.
+func (gazetteer *Gazetteer) LoadCities(r io.Reader) error {
+	var places []Place
+	scanner := bufio.NewScanner(r)
+	// the alternate names make long lines
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if strings.TrimSpace(text) == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Split(text, "\t")
+		if len(fields) < 15 {
+			return fmt.Errorf("Error loading cities: line %d has %d columns, expected at least 15", line, len(fields))
+		}
+		lat, errLat := strconv.ParseFloat(fields[4], 64)
+		lng, errLng := strconv.ParseFloat(fields[5], 64)
+		if errLat != nil || errLng != nil {
+			return fmt.Errorf("Error loading cities: invalid coordinates on line %d", line)
+		}
+		place := Place{
+			ID:          fields[0],
+			Name:        fields[1],
+			LatLng:      LatLng{Lat: lat, Lng: lng},
+			CountryCode: fields[8],
+			Admin1Code:  fields[10],
+			Admin2Code:  fields[11],
+		}
+		if err := place.LatLng.Validate(); err != nil {
+			return fmt.Errorf("Error loading cities: line %d: %v", line, err)
+		}
+		place.Population, _ = strconv.Atoi(fields[14])
+		places = append(places, place)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("Error loading cities: <%v>", err)
+	}
+	gazetteer.AddPlaces(places...)
+	return nil
+}
+
+// LoadAdminCodes adds the names of the GeoNames admin codes of the places,
+// from admin1CodesASCII.txt or admin2Codes.txt: tab separated code (eg
+// "US.WA" or "US.WA.033"), name, asciiname, geonameid.
+func (gazetteer *Gazetteer) LoadAdminCodes(r io.Reader) error {
+	names := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if strings.TrimSpace(text) == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Split(text, "\t")
+		if len(fields) < 2 || !strings.Contains(fields[0], ".") {
+			return fmt.Errorf("Error loading admin codes: invalid code on line %d", line)
+		}
+		names[fields[0]] = fields[1]
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("Error loading admin codes: <%v>", err)
+	}
+	gazetteer.mutex.Lock()
+	defer gazetteer.mutex.Unlock()
+	if gazetteer.adminNames == nil {
+		gazetteer.adminNames = make(map[string]string, len(names))
+	}
+	for code, name := range names {
+		gazetteer.adminNames[code] = name
+	}
+	return nil
+}
+
+// adminFeatures is a GeoJSON feature collection of admin areas
+type adminFeatures struct {
+	Features []struct {
+		Geometry struct {
+			Type        string          `json:"type"`
+			Coordinates json.RawMessage `json:"coordinates"`
+		} `json:"geometry"`
+		Properties Location `json:"properties"`
+	} `json:"features"`
+}
+
+// rings converts GeoJSON [lng, lat] rings into points
+func rings(coordinates [][][]float64) [][]LatLng {
+	polygon := make([][]LatLng, len(coordinates))
+	for i, ring := range coordinates {
+		polygon[i] = make([]LatLng, len(ring))
+		for j, position := range ring {
+			if len(position) >= 2 {
+				polygon[i][j] = LatLng{Lat: position[1], Lng: position[0]}
+			}
+		}
+	}
+	return polygon
+}
+
+// LoadAdminAreas adds the admin areas of a GeoJSON feature collection of
+// Polygon and MultiPolygon features. The properties use the keys of a
+// location, eg {"adminArea1": "US", "adminArea3": "WA", "adminArea3Type": "State"}.
+// Nothing is added if a feature is invalid.
+func (gazetteer *Gazetteer) LoadAdminAreas(r io.Reader) error {
+	var collection adminFeatures
+	if err := json.NewDecoder(r).Decode(&collection); err != nil {
+		return fmt.Errorf("Error decoding admin areas: <%v>", err)
+	}
+	var areas []adminArea
+	for i, feature := range collection.Features {
+		area := adminArea{location: feature.Properties}
+		switch feature.Geometry.Type {
+		case "Polygon":
+			var coordinates [][][]float64
+			if err := json.Unmarshal(feature.Geometry.Coordinates, &coordinates); err != nil {
+				return fmt.Errorf("Error decoding admin area %d: <%v>", i, err)
+			}
+			area.polygons = [][][]LatLng{rings(coordinates)}
+		case "MultiPolygon":
+			var coordinates [][][][]float64
+			if err := json.Unmarshal(feature.Geometry.Coordinates, &coordinates); err != nil {
+				return fmt.Errorf("Error decoding admin area %d: <%v>", i, err)
+			}
+			for _, polygon := range coordinates {
+				area.polygons = append(area.polygons, rings(polygon))
+			}
+		default:
+			return fmt.Errorf("Error decoding admin area %d: unsupported geometry %q", i, feature.Geometry.Type)
+		}
+		for _, polygon := range area.polygons {
+			if len(polygon) == 0 || len(polygon[0]) < 3 {
+				return fmt.Errorf("Error decoding admin area %d: polygon without outer ring", i)
+			}
+			area.bboxes = append(area.bboxes, NewBBox(polygon[0]))
+		}
+		areas = append(areas, area)
+	}
+	gazetteer.mutex.Lock()
+	defer gazetteer.mutex.Unlock()
+	gazetteer.areas = append(gazetteer.areas, areas...)
+	return nil
+}
+
+// Nearest returns the place nearest to the point and its distance in meters,
+// false if the gazetteer has no places.
+func (gazetteer *Gazetteer) Nearest(point LatLng) (Place, float64, bool) {
+	places, index := gazetteer.placeIndex()
+	if index == nil {
+		return Place{}, 0, false
+	}
+	neighbors := index.Nearest(point, 1)
+	if len(neighbors) == 0 {
+		return Place{}, 0, false
+	}
+	return places[neighbors[0].Index], neighbors[0].Distance * Kilometers.meters(), true
+}
+
+// AdminAreas returns the admin areas containing the point, in load order
+func (gazetteer *Gazetteer) AdminAreas(point LatLng) []Location {
+	gazetteer.mutex.RLock()
+	areas := gazetteer.areas
+	gazetteer.mutex.RUnlock()
+	var locations []Location
+	for _, area := range areas {
+		if area.contains(point) {
+			locations = append(locations, area.location)
+		}
+	}
+	return locations
+}
+
+// mergeLocation fills the empty address fields of a location
+func mergeLocation(location *Location, other Location) {
+	fields := [][2]*string{
+		{&location.Neighborhood, &other.Neighborhood}, {&location.NeighborhoodType, &other.NeighborhoodType},
+		{&location.City, &other.City}, {&location.CityType, &other.CityType},
+		{&location.County, &other.County}, {&location.CountyType, &other.CountyType},
+		{&location.State, &other.State}, {&location.StateType, &other.StateType},
+		{&location.CountryCode, &other.CountryCode}, {&location.CountryType, &other.CountryType},
+		{&location.PostalCode, &other.PostalCode},
+	}
+	for _, field := range fields {
+		if *field[0] == "" {
+			*field[0] = *field[1]
+		}
+	}
+}
+
+// ReverseGeocode returns the admin areas containing the point and the
+// nearest place within MaxDistance. The first area (in load order) providing
+// a field wins, the nearest place fills the remaining fields unless it lies
+// in another country. ErrNoPlace is returned if nothing is found.
+func (gazetteer *Gazetteer) ReverseGeocode(point LatLng) (*Location, error) {
+	point = point.Normalize()
+	if err := point.Validate(); err != nil {
+		return nil, err
+	}
+	location := Location{LatLng: point}
+	for _, area := range gazetteer.AdminAreas(point) {
+		mergeLocation(&location, area)
+	}
+	place, distance, ok := gazetteer.Nearest(point)
+	if ok && (gazetteer.MaxDistance <= 0 || distance <= gazetteer.MaxDistance) &&
+		(location.CountryCode == "" || strings.EqualFold(location.CountryCode, place.CountryCode)) {
+		gazetteer.mutex.RLock()
+		mergeLocation(&location, place.location(gazetteer.adminNames))
+		gazetteer.mutex.RUnlock()
+	}
+	switch {
+	case location.Neighborhood != "":
+		location.GeocodeQuality = "NEIGHBORHOOD"
+	case location.City != "":
+		location.GeocodeQuality = "CITY"
+	case location.County != "":
+		location.GeocodeQuality = "COUNTY"
+	case location.State != "":
+		location.GeocodeQuality = "STATE"
+	case location.CountryCode != "":
+		location.GeocodeQuality = "COUNTRY"
+	default:
+		return nil, ErrNoPlace
+	}
+	return &location, nil
+}
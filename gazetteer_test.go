@@ -0,0 +1,142 @@
+package geocoder
+
+import (
+	"strings"
+	"testing"
+)
+
+const testCities = "# geonameid\tname\tasciiname\talternatenames\tlatitude\tlongitude\tclass\tcode\tcountry\tcc2\tadmin1\tadmin2\tadmin3\tadmin4\tpopulation\n" +
+	"5809844\tSeattle\tSeattle\tSea\t47.60621\t-122.33207\tP\tPPLA2\tUS\t\tWA\t033\t\t\t737015\n" +
+	"5799841\tKirkland\tKirkland\t\t47.68149\t-122.20874\tP\tPPL\tUS\t\tWA\t033\t\t\t92175\n" +
+	"6173331\tVancouver\tVancouver\t\t49.24966\t-123.11934\tP\tPPL\tCA\t\t02\t5915022\t\t\t631486\n" +
+	"5786010\tBlaine\tBlaine\t\t48.99372\t-122.74709\tP\tPPL\tUS\t\tWA\t073\t\t\t5231\n" +
+	"5814616\tVancouver\tVancouver\t\t45.63873\t-122.66149\tP\tPPLA2\tUS\t\tWA\t011\t\t\t190915\n"
+
+const testAdminAreas = `{"type": "FeatureCollection", "features": [
+	{"type": "Feature", "properties": {"adminArea4": "King", "adminArea4Type": "County"},
+	 "geometry": {"type": "Polygon", "coordinates": [[[-122.6, 47.1], [-121.0, 47.1], [-121.0, 47.8], [-122.6, 47.8], [-122.6, 47.1]]]}},
+	{"type": "Feature", "properties": {"adminArea1": "US", "adminArea1Type": "Country", "adminArea3": "Washington", "adminArea3Type": "State"},
+	 "geometry": {"type": "MultiPolygon", "coordinates": [
+		[[[-124.8, 45.5], [-116.9, 45.5], [-116.9, 49.0], [-124.8, 49.0], [-124.8, 45.5]],
+		 [[-124.0, 46.0], [-123.5, 46.0], [-123.5, 46.5], [-124.0, 46.5], [-124.0, 46.0]]]]}},
+	{"type": "Feature", "properties": {"adminArea1": "CA", "adminArea1Type": "Country", "adminArea3": "BC", "adminArea3Type": "Province"},
+	 "geometry": {"type": "Polygon", "coordinates": [[[-139.0, 49.0], [-114.0, 49.0], [-114.0, 60.0], [-139.0, 60.0], [-139.0, 49.0]]]}}
+]}`
+
+const testAdminCodes = "CA.02\tBritish Columbia\tBritish Columbia\t5909050\n" +
+	"CA.02.5915022\tGreater Vancouver\tGreater Vancouver\t5965814\n"
+
+func testGazetteer(t *testing.T) *Gazetteer {
+	gazetteer := NewGazetteer()
+	unexpected(gazetteer.LoadCities(strings.NewReader(testCities)), t)
+	unexpected(gazetteer.LoadAdminAreas(strings.NewReader(testAdminAreas)), t)
+	return gazetteer
+}
+
+func TestGazetteerNearest(t *testing.T) {
+	gazetteer := testGazetteer(t)
+	place, distance, ok := gazetteer.Nearest(LatLng{Lat: 47.67, Lng: -122.2})
+	if !ok || place.Name != "Kirkland" || place.Population != 92175 || distance > 2000 {
+		t.Errorf("Expected Kirkland ~ Received %v at %v m", place, distance)
+	}
+	if _, _, ok := NewGazetteer().Nearest(LatLng{}); ok {
+		t.Errorf("Expected no place in an empty gazetteer")
+	}
+	// places added in several calls are indexed together
+	gazetteer = &Gazetteer{}
+	gazetteer.AddPlaces(Place{Name: "Seattle", LatLng: LatLng{Lat: 47.60621, Lng: -122.33207}})
+	gazetteer.Nearest(LatLng{})
+	gazetteer.AddPlaces(Place{Name: "Kirkland", LatLng: LatLng{Lat: 47.68149, Lng: -122.20874}})
+	if place, _, ok := gazetteer.Nearest(LatLng{Lat: 47.67, Lng: -122.2}); !ok || place.Name != "Kirkland" {
+		t.Errorf("Expected Kirkland ~ Received %v", place)
+	}
+}
+
+func TestGazetteerZeroValue(t *testing.T) {
+	var gazetteer Gazetteer
+	if _, _, ok := gazetteer.Nearest(LatLng{Lat: 47.6, Lng: -122.3}); ok {
+		t.Errorf("Expected no place in a zero value gazetteer")
+	}
+	if location, err := gazetteer.ReverseGeocode(LatLng{Lat: 47.6, Lng: -122.3}); err != ErrNoPlace {
+		t.Errorf("Expected ErrNoPlace ~ Received %v, %v", location, err)
+	}
+}
+
+func TestGazetteerReverseGeocode(t *testing.T) {
+	var geocoder ReverseGeocoder = testGazetteer(t)
+	location, err := geocoder.ReverseGeocode(LatLng{Lat: 47.61, Lng: -122.34})
+	if unexpected(err, t) {
+		return
+	}
+	if location.City != "Seattle" || location.County != "King" || location.State != "Washington" ||
+		location.CountryCode != "US" || location.GeocodeQuality != "CITY" || location.StateType != "State" {
+		t.Errorf("Expected Seattle, King, Washington, US ~ Received %+v", *location)
+	}
+	// in the hole of the state polygon and too far from any city
+	location, err = geocoder.ReverseGeocode(LatLng{Lat: 46.25, Lng: -123.75})
+	if err != ErrNoPlace {
+		t.Errorf("Expected ErrNoPlace ~ Received %v, %v", location, err)
+	}
+	// the nearest city (Blaine WA) lies in another country
+	location, err = geocoder.ReverseGeocode(LatLng{Lat: 49.01, Lng: -122.74})
+	if unexpected(err, t) {
+		return
+	}
+	if location.CountryCode != "CA" || location.City != "" || location.State != "BC" || location.GeocodeQuality != "STATE" {
+		t.Errorf("Expected BC, CA ~ Received %+v", *location)
+	}
+	location, err = geocoder.ReverseGeocode(LatLng{Lat: 49.2, Lng: -123.1})
+	if unexpected(err, t) {
+		return
+	}
+	// the admin codes of the place are not names
+	if location.City != "Vancouver" || location.State != "BC" || location.County != "" {
+		t.Errorf("Expected Vancouver, BC ~ Received %+v", *location)
+	}
+	if place, _, _ := testGazetteer(t).Nearest(LatLng{Lat: 49.2, Lng: -123.1}); place.Admin1Code != "02" || place.Admin2Code != "5915022" {
+		t.Errorf("Expected admin codes 02 and 5915022 ~ Received %+v", place)
+	}
+	if _, err := geocoder.ReverseGeocode(LatLng{Lat: 95, Lng: 0}); err == nil {
+		t.Errorf("Expected an error for an invalid point")
+	}
+}
+
+func TestGazetteerAdminCodes(t *testing.T) {
+	gazetteer := NewGazetteer()
+	unexpected(gazetteer.LoadCities(strings.NewReader(testCities)), t)
+	unexpected(gazetteer.LoadAdminCodes(strings.NewReader(testAdminCodes)), t)
+	location, err := gazetteer.ReverseGeocode(LatLng{Lat: 49.2, Lng: -123.1})
+	if unexpected(err, t) {
+		return
+	}
+	if location.City != "Vancouver" || location.State != "British Columbia" || location.County != "Greater Vancouver" || location.CountyType != "County" {
+		t.Errorf("Expected Vancouver, Greater Vancouver, British Columbia ~ Received %+v", *location)
+	}
+	// codes without names are left out
+	location, err = gazetteer.ReverseGeocode(LatLng{Lat: 47.61, Lng: -122.34})
+	if !unexpected(err, t) && (location.State != "" || location.County != "" || location.GeocodeQuality != "CITY") {
+		t.Errorf("Expected Seattle without state and county ~ Received %+v", *location)
+	}
+	if err := gazetteer.LoadAdminCodes(strings.NewReader("WA\tWashington\n")); err == nil {
+		t.Errorf("Expected an error for a code without country")
+	}
+}
+
+func TestGazetteerLoadErrors(t *testing.T) {
+	gazetteer := NewGazetteer()
+	if err := gazetteer.LoadCities(strings.NewReader("1\tSeattle\t47.6\n")); err == nil {
+		t.Errorf("Expected an error for missing columns")
+	}
+	if err := gazetteer.LoadAdminAreas(strings.NewReader(`{"features": [{"geometry": {"type": "Point", "coordinates": [0, 0]}}]}`)); err == nil {
+		t.Errorf("Expected an error for a point geometry")
+	}
+	// a valid area before the invalid one is not added either
+	if err := gazetteer.LoadAdminAreas(strings.NewReader(`{"features": [
+	{"properties": {"adminArea1": "NL"}, "geometry": {"type": "Polygon", "coordinates": [[[3, 50], [7, 50], [7, 54], [3, 54], [3, 50]]]}},
+	{"geometry": {"type": "Point", "coordinates": [0, 0]}}]}`)); err == nil {
+		t.Errorf("Expected an error for a point geometry")
+	}
+	if areas := gazetteer.AdminAreas(LatLng{Lat: 52, Lng: 5}); len(areas) != 0 {
+		t.Errorf("Expected no admin areas ~ Received %v", areas)
+	}
+}
@@ -4,7 +4,10 @@ package geocoder
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 )
 
 var apiKey = "Fmjtd%7Cluub256alu%2C7s%3Do5-9u82ur"
@@ -29,15 +32,114 @@ type LatLng struct {
 
 // Location is specified by its address and coordinates
 type Location struct {
-	Street      string `json:"street"`
-	City        string `json:"adminArea5"`
-	State       string `json:"adminArea3"`
-	PostalCode  string `json:"postalCode"`
-	County      string `json:"adminArea4"`
-	CountryCode string `json:"adminArea1"`
-	LatLng      LatLng `json:"latLng"`
-	Type        string `json:"type"`
-	DragPoint   bool   `json:"dragPoint"`
+	Street       string `json:"street"`
+	Neighborhood string `json:"adminArea6"`
+	City         string `json:"adminArea5"`
+	State        string `json:"adminArea3"`
+	PostalCode   string `json:"postalCode"`
+	County       string `json:"adminArea4"`
+	CountryCode  string `json:"adminArea1"`
+	LatLng       LatLng `json:"latLng"`
+	Type         string `json:"type"`
+	DragPoint    bool   `json:"dragPoint"`
+	// Kind of the admin areas, eg "City", "County", "State", "Country"
+	NeighborhoodType string `json:"adminArea6Type"`
+	CityType         string `json:"adminArea5Type"`
+	CountyType       string `json:"adminArea4Type"`
+	StateType        string `json:"adminArea3Type"`
+	CountryType      string `json:"adminArea1Type"`
+	// ex: "NEIGHBORHOOD", "CITY", "COUNTY"
+	GeocodeQuality     string `json:"geocodeQuality"`
+	GeocodeQualityCode string `json:"geocodeQualityCode"`
+	// L(eft), R(ight), N(one) or M(ixed)
+	SideOfStreet  string `json:"sideOfStreet"`
+	LinkID        LinkID `json:"linkId"`
+	UnknownInput  string `json:"unknownInput"`
+	DisplayLatLng LatLng `json:"displayLatLng"`
+	MapURL        string `json:"mapUrl"`
+}
+
+// LinkID identifies a road link. Mapquest sends it as a number or as a
+// string, which may be empty or composite (eg "r123|i4").
+type LinkID string
+
+// UnmarshalJSON accepts a json number as well as a string
+func (id *LinkID) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*id = LinkID(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("Invalid linkId %s", data)
+	}
+	*id = LinkID(n)
+	return nil
+}
+
+// MarshalJSON writes numeric ids as numbers, like mapquest sends them.
+// Ids which do not read back the same (eg "007" or "+5") stay strings.
+func (id LinkID) MarshalJSON() ([]byte, error) {
+	if n, err := strconv.ParseInt(string(id), 10, 64); err == nil && strconv.FormatInt(n, 10) == string(id) {
+		return []byte(id), nil
+	}
+	return json.Marshal(string(id))
+}
+
+// locationBody is a location as sent in the body of a request.
+// Empty fields are left out, so a location may be an address, a LatLng or both.
+type locationBody struct {
+	Street       string  `json:"street,omitempty"`
+	City         string  `json:"adminArea5,omitempty"`
+	County       string  `json:"adminArea4,omitempty"`
+	State        string  `json:"adminArea3,omitempty"`
+	PostalCode   string  `json:"postalCode,omitempty"`
+	CountryCode  string  `json:"adminArea1,omitempty"`
+	LatLng       *LatLng `json:"latLng,omitempty"`
+	Type         string  `json:"type,omitempty"`
+	SideOfStreet string  `json:"sideOfStreet,omitempty"`
+	DragPoint    bool    `json:"dragPoint,omitempty"`
+}
+
+// body converts the location for use in a request body
+func (location Location) body() locationBody {
+	body := locationBody{
+		Street:      location.Street,
+		City:        location.City,
+		County:      location.County,
+		State:       location.State,
+		PostalCode:  location.PostalCode,
+		CountryCode: location.CountryCode,
+		Type:        location.Type,
+		DragPoint:   location.DragPoint,
+	}
+	if location.LatLng != (LatLng{}) {
+		latLng := location.LatLng.Normalize()
+		body.LatLng = &latLng
+	}
+	return body
+}
+
+// singleLine formats the location as a single line address,
+// or as a normalized "lat,lng" when it has no address.
+func (location Location) singleLine() string {
+	var parts []string
+	for _, part := range []string{location.Street, location.City, location.County,
+		strings.TrimSpace(location.State + " " + location.PostalCode), location.CountryCode} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return location.LatLng.Normalize().String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+// String formats the point as "lat,lng"
+func (latLng LatLng) String() string {
+	return strconv.FormatFloat(latLng.Lat, 'f', -1, 64) + "," + strconv.FormatFloat(latLng.Lng, 'f', -1, 64)
 }
 
 // Complete geocoding result
@@ -75,7 +177,7 @@ type GeocodingResult struct {
 			GeocodeQuality string `json:"geocodeQuality"`
 			DragPoint      bool   `json:"dragPoint"`
 			SideOfStreet   string `json:"sideOfStreet"`
-			LinkId         string `json:"linkId"`
+			LinkId         LinkID `json:"linkId"`
 			UnknownInput   string `json:"unknownInput"`
 			Type           string `json:"type"`
 			LatLng         LatLng `json:"latLng"`
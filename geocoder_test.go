@@ -1,6 +1,9 @@
 package geocoder
 
-import "testing"
+import (
+	"encoding/json"
+	"testing"
+)
 
 func TestSetAPIKey(t *testing.T) {
 	key := apiKey
@@ -10,3 +13,44 @@ func TestSetAPIKey(t *testing.T) {
 	}
 	SetAPIKey(key)
 }
+
+func TestLocationLinkID(t *testing.T) {
+	for data, expected := range map[string]LinkID{
+		`{"street": "542 Marion St", "linkId": ""}`:        "",
+		`{"street": "542 Marion St", "linkId": "r123|i4"}`: "r123|i4",
+		`{"street": "542 Marion St", "linkId": 282041090}`: "282041090",
+		`{"street": "542 Marion St", "linkId": null}`:      "",
+	} {
+		var location Location
+		if err := json.Unmarshal([]byte(data), &location); unexpected(err, t) {
+			continue
+		}
+		if location.LinkID != expected || location.Street != "542 Marion St" {
+			t.Errorf("Expected %q ~ Received %q", expected, location.LinkID)
+		}
+	}
+	var location Location
+	if err := json.Unmarshal([]byte(`{"linkId": {}}`), &location); err == nil {
+		t.Errorf("Expected an error for an object linkId")
+	}
+}
+
+func TestGeocodingResultLinkID(t *testing.T) {
+	var result GeocodingResult
+	data := `{"results": [{"locations": [{"linkId": 282041090}, {"linkId": "r123|i4"}]}]}`
+	if err := json.Unmarshal([]byte(data), &result); unexpected(err, t) {
+		return
+	}
+	locations := result.Results[0].Locations
+	if locations[0].LinkId != "282041090" || locations[1].LinkId != "r123|i4" {
+		t.Errorf("Expected 282041090 and r123|i4 ~ Received %q and %q", locations[0].LinkId, locations[1].LinkId)
+	}
+}
+
+func TestLinkIDMarshal(t *testing.T) {
+	for id, expected := range map[LinkID]string{"282041090": `282041090`, "": `""`, "r123|i4": `"r123|i4"`, "007": `"007"`, "+5": `"+5"`} {
+		if data, err := json.Marshal(id); !unexpected(err, t) && string(data) != expected {
+			t.Errorf("Expected %s ~ Received %s", expected, data)
+		}
+	}
+}
@@ -2,6 +2,10 @@
 
 Reference: http://open.mapquestapi.com/geocoding/
 
+Unambiguous coordinate strings (symbols, decimals, a comma separated pair,
+UTM or MGRS like "4QFJ1234") are not geocoded: Geocode returns them as is and
+FullGeocode reverse geocodes them.
+
 Example:
 
 lat, lng := Geocode("Seattle WA")
@@ -28,6 +32,10 @@ const (
 // Returns the latitude and longitude of the best location match
 // for the specified query.
 func Geocode(address string) (float64, float64, error) {
+	if point, ok := coordinateQuery(address); ok {
+		return point.Lat, point.Lng, nil
+	}
+
 	// Query Provider
 	resp, err := http.Get(geocodeURL + url.QueryEscape(address) + "&key=" + apiKey)
 
@@ -58,8 +66,26 @@ func Geocode(address string) (float64, float64, error) {
 // Returns the full geocoding response including all of the matches
 // as well as reverse-geocoded for each match location.
 func FullGeocode(address string) (*GeocodingResult, error) {
+	if point, ok := coordinateQuery(address); ok {
+		return fullReverseGeocode(address, point)
+	}
+	return fullGeocode(geocodeURL + url.QueryEscape(address) + "&key=" + apiKey)
+}
+
+// Returns the full geocoding response like FullGeocode, preferring
+// matches within the bounding box.
+func FullGeocodeWithin(address string, bbox BBox) (*GeocodingResult, error) {
+	if point, ok := coordinateQuery(address); ok {
+		return fullReverseGeocode(address, point)
+	}
+	return fullGeocode(geocodeURL + url.QueryEscape(address) + "&boundingBox=" +
+		url.QueryEscape(bbox.String()) + "&key=" + apiKey)
+}
+
+// fullGeocode requests and decodes a full geocoding response
+func fullGeocode(requestURL string) (*GeocodingResult, error) {
 	// Query Provider
-	resp, err := http.Get(geocodeURL + url.QueryEscape(address) + "&key=" + apiKey)
+	resp, err := http.Get(requestURL)
 
 	if err != nil {
 		return nil, fmt.Errorf("Error geocoding address: <%v>", err)
@@ -78,11 +104,35 @@ func FullGeocode(address string) (*GeocodingResult, error) {
 	return &result, nil
 }
 
+// fullReverseGeocode returns the full reverse geocoding response of a point
+// parsed from a coordinate string, as provided location.
+func fullReverseGeocode(address string, point LatLng) (*GeocodingResult, error) {
+	result, err := fullGeocode(reverseGeocodeRequestURL(point))
+	if err != nil {
+		return nil, err
+	}
+	for i := range result.Results {
+		result.Results[i].ProvidedLocation.Location = address
+	}
+	return result, nil
+}
+
+// reverseGeocodeRequestURL returns the reverse geocoding url of a point
+// (rounded to 6 decimals, about 10 cm)
+func reverseGeocodeRequestURL(point LatLng) string {
+	return reverseGeocodeURL + point.Round(6).String() + "&key=" + apiKey
+}
+
 // Returns the address for a latitude and longitude.
+// The longitude is normalized and invalid points are rejected without a request.
 func ReverseGeocode(lat float64, lng float64) (*Location, error) {
+	point := LatLng{Lat: lat, Lng: lng}.Normalize()
+	if err := point.Validate(); err != nil {
+		return nil, fmt.Errorf("Error reverse geocoding lat, long pair: <%v>", err)
+	}
+
 	// Query Provider
-	resp, err := http.Get(reverseGeocodeURL +
-		fmt.Sprintf("%f,%f&key=%s", lat, lng, apiKey))
+	resp, err := http.Get(reverseGeocodeRequestURL(point))
 
 	if err != nil {
 		return nil, fmt.Errorf("Error reverse geocoding lat, long pair: <%v>", err)
@@ -155,6 +205,18 @@ func BatchGeocode(addresses []string) ([]LatLng, error) {
 	return latLngs, nil
 }
 
+// LatLngs returns the best match of each result (as BatchGeocode does),
+// a zero LatLng for results without locations.
+func (result GeocodingResult) LatLngs() []LatLng {
+	latLngs := make([]LatLng, len(result.Results))
+	for i, r := range result.Results {
+		if len(r.Locations) > 0 {
+			latLngs[i] = r.Locations[0].LatLng
+		}
+	}
+	return latLngs
+}
+
 // geocodingResults contains the locations of a geocoding request
 // MapQuest providers more JSON fields than this but this is all we are interested in.
 type geocodingResults struct {
@@ -0,0 +1,201 @@
+/* Offline geodesic calculations on LatLng.
+
+Distances are given in a Unit: m (Miles) or k (Km), as for Directions.Distance.
+The great circle functions use a spherical earth (mean radius), VincentyDistance
+uses the WGS84 ellipsoid and is accurate to less than a millimeter.
+
+Example:
+
+amsterdam := LatLng{Lat: 52.37403, Lng: 4.88969}
+antwerp := LatLng{Lat: 51.22111, Lng: 4.399708}
+distance := amsterdam.Distance(antwerp, Kilometers)
+bearing := amsterdam.Bearing(antwerp)
+
+*/
+
+package geocoder
+
+import (
+	"errors"
+	"math"
+)
+
+// mean earth radius in meters
+const earthRadius = 6371008.8
+
+// WGS84 ellipsoid
+const (
+	wgs84A = 6378137.0
+	wgs84F = 1 / 298.257223563
+	wgs84B = wgs84A * (1 - wgs84F)
+)
+
+// ErrVincentyConvergence is returned when Vincenty's formula does not
+// converge, which happens for (nearly) antipodal points.
+var ErrVincentyConvergence = errors.New("Vincenty's formula failed to converge")
+
+// meters returns the length of a unit in meters
+func (unit Unit) meters() float64 {
+	if unit == Kilometers {
+		return 1000
+	}
+	return 1609.344
+}
+
+// radians converts degrees into radians
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
+
+// degrees converts radians into degrees
+func degrees(radians float64) float64 {
+	return radians * 180 / math.Pi
+}
+
+// normalizeBearing returns a bearing in [0, 360)
+func normalizeBearing(bearing float64) float64 {
+	return math.Mod(math.Mod(bearing, 360)+360, 360)
+}
+
+// normalizeLng returns a longitude in [-180, 180)
+func normalizeLng(lng float64) float64 {
+	if lng >= -180 && lng < 180 {
+		return lng
+	}
+	return math.Mod(math.Mod(lng+180, 360)+360, 360) - 180
+}
+
+// angularDistance is the great circle distance in radians (haversine formula)
+func angularDistance(from, to LatLng) float64 {
+	lat1, lat2 := radians(from.Lat), radians(to.Lat)
+	dLat, dLng := lat2-lat1, radians(to.Lng-from.Lng)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
+
+// haversine is the great circle distance in meters
+func haversine(from, to LatLng) float64 {
+	return angularDistance(from, to) * earthRadius
+}
+
+// Distance returns the great circle (haversine) distance to another point in unit.
+func (latLng LatLng) Distance(to LatLng, unit Unit) float64 {
+	return haversine(latLng, to) / unit.meters()
+}
+
+// VincentyDistance returns the distance to another point on the WGS84
+// ellipsoid in unit.
+func (latLng LatLng) VincentyDistance(to LatLng, unit Unit) (float64, error) {
+	u1 := math.Atan((1 - wgs84F) * math.Tan(radians(latLng.Lat)))
+	u2 := math.Atan((1 - wgs84F) * math.Tan(radians(to.Lat)))
+	sinU1, cosU1 := math.Sincos(u1)
+	sinU2, cosU2 := math.Sincos(u2)
+	l := radians(to.Lng - latLng.Lng)
+	lambda := l
+	var sinSigma, cosSigma, sigma, cos2Alpha, cos2SigmaM float64
+	for i := 0; ; i++ {
+		if i == 200 {
+			return 0, ErrVincentyConvergence
+		}
+		sinLambda, cosLambda := math.Sincos(lambda)
+		sinSigma = math.Hypot(cosU2*sinLambda, cosU1*sinU2-sinU1*cosU2*cosLambda)
+		if sinSigma == 0 {
+			// coincident points
+			return 0, nil
+		}
+		cosSigma = sinU1*sinU2 + cosU1*cosU2*cosLambda
+		sigma = math.Atan2(sinSigma, cosSigma)
+		sinAlpha := cosU1 * cosU2 * sinLambda / sinSigma
+		cos2Alpha = 1 - sinAlpha*sinAlpha
+		cos2SigmaM = 0
+		if cos2Alpha != 0 {
+			// not an equatorial line
+			cos2SigmaM = cosSigma - 2*sinU1*sinU2/cos2Alpha
+		}
+		c := wgs84F / 16 * cos2Alpha * (4 + wgs84F*(4-3*cos2Alpha))
+		previous := lambda
+		lambda = l + (1-c)*wgs84F*sinAlpha*(sigma+c*sinSigma*(cos2SigmaM+c*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+		if math.Abs(lambda-previous) < 1e-12 {
+			break
+		}
+	}
+	uSq := cos2Alpha * (wgs84A*wgs84A - wgs84B*wgs84B) / (wgs84B * wgs84B)
+	a := 1 + uSq/16384*(4096+uSq*(-768+uSq*(320-175*uSq)))
+	b := uSq / 1024 * (256 + uSq*(-128+uSq*(74-47*uSq)))
+	deltaSigma := b * sinSigma * (cos2SigmaM + b/4*(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-
+		b/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+	return wgs84B * a * (sigma - deltaSigma) / unit.meters(), nil
+}
+
+// Bearing returns the initial bearing (degrees clockwise from north, [0, 360))
+// of the great circle to another point.
+func (latLng LatLng) Bearing(to LatLng) float64 {
+	lat1, lat2 := radians(latLng.Lat), radians(to.Lat)
+	dLng := radians(to.Lng - latLng.Lng)
+	y := math.Sin(dLng) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLng)
+	return normalizeBearing(degrees(math.Atan2(y, x)))
+}
+
+// FinalBearing returns the bearing at which the great circle arrives at another point.
+func (latLng LatLng) FinalBearing(to LatLng) float64 {
+	return normalizeBearing(to.Bearing(latLng) + 180)
+}
+
+// destination returns the point at a distance (meters) and bearing (degrees)
+// from start along a great circle.
+func destination(start LatLng, bearing, distance float64) LatLng {
+	lat1 := radians(start.Lat)
+	lng1 := radians(start.Lng)
+	theta := radians(bearing)
+	delta := distance / earthRadius
+	lat2 := math.Asin(math.Sin(lat1)*math.Cos(delta) + math.Cos(lat1)*math.Sin(delta)*math.Cos(theta))
+	lng2 := lng1 + math.Atan2(math.Sin(theta)*math.Sin(delta)*math.Cos(lat1), math.Cos(delta)-math.Sin(lat1)*math.Sin(lat2))
+	return LatLng{Lat: degrees(lat2), Lng: normalizeLng(degrees(lng2))}
+}
+
+// Destination returns the point at a distance (in unit) and bearing (degrees)
+// along a great circle.
+func (latLng LatLng) Destination(bearing, distance float64, unit Unit) LatLng {
+	return destination(latLng, bearing, distance*unit.meters())
+}
+
+// Midpoint returns the point halfway along the great circle to another point.
+func (latLng LatLng) Midpoint(to LatLng) LatLng {
+	lat1, lat2 := radians(latLng.Lat), radians(to.Lat)
+	lng1 := radians(latLng.Lng)
+	dLng := radians(to.Lng - latLng.Lng)
+	bx := math.Cos(lat2) * math.Cos(dLng)
+	by := math.Cos(lat2) * math.Sin(dLng)
+	lat := math.Atan2(math.Sin(lat1)+math.Sin(lat2), math.Hypot(math.Cos(lat1)+bx, by))
+	lng := lng1 + math.Atan2(by, math.Cos(lat1)+bx)
+	return LatLng{Lat: degrees(lat), Lng: normalizeLng(degrees(lng))}
+}
+
+// crossTrack returns the cross track and along track angular distances
+// of point to the great circle from start to end.
+func crossTrack(point, start, end LatLng) (cross, along float64) {
+	delta13 := angularDistance(start, point)
+	theta := radians(start.Bearing(point) - start.Bearing(end))
+	cross = math.Asin(math.Sin(delta13) * math.Sin(theta))
+	along = math.Acos(math.Max(-1, math.Min(1, math.Cos(delta13)/math.Cos(cross))))
+	if math.Cos(theta) < 0 {
+		along = -along
+	}
+	return
+}
+
+// CrossTrackDistance returns the distance (in unit) of the point to the
+// great circle through start and end, negative left of the path.
+func (latLng LatLng) CrossTrackDistance(start, end LatLng, unit Unit) float64 {
+	cross, _ := crossTrack(latLng, start, end)
+	return cross * earthRadius / unit.meters()
+}
+
+// AlongTrackDistance returns the distance (in unit) from start to the point
+// on the great circle through start and end closest to the point,
+// negative if that point lies before start.
+func (latLng LatLng) AlongTrackDistance(start, end LatLng, unit Unit) float64 {
+	_, along := crossTrack(latLng, start, end)
+	return along * earthRadius / unit.meters()
+}
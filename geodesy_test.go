@@ -0,0 +1,89 @@
+package geocoder
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDestination(t *testing.T) {
+	// one degree of latitude to the north
+	point := destination(LatLng{Lat: 0, Lng: 0}, 0, earthRadius*math.Pi/180)
+	if math.Abs(point.Lat-1) > 1e-9 || math.Abs(point.Lng) > 1e-9 {
+		t.Errorf("Expected (1, 0) ~ Received %v", point)
+	}
+	// across the antimeridian
+	point = LatLng{Lat: 0, Lng: 179.5}.Destination(90, earthRadius*math.Pi/180/1000, Kilometers)
+	if math.Abs(point.Lng+179.5) > 1e-9 {
+		t.Errorf("Expected lng -179.5 ~ Received %v", point)
+	}
+}
+
+func TestGreatCircleDistance(t *testing.T) {
+	london, newYork := LatLng{Lat: 51.5007, Lng: -0.1246}, LatLng{Lat: 40.6892, Lng: -74.0445}
+	if distance := london.Distance(newYork, Kilometers); math.Abs(distance-5574.8) > 0.1 {
+		t.Errorf("Expected 5574.8 ~ Received %f", distance)
+	}
+	if distance := london.Distance(newYork, Miles); math.Abs(distance-3464.0) > 0.1 {
+		t.Errorf("Expected 3464.0 ~ Received %f", distance)
+	}
+}
+
+func TestVincentyDistance(t *testing.T) {
+	flindersPeak := LatLng{Lat: -37.951033417, Lng: 144.424867889}
+	buninyong := LatLng{Lat: -37.652821139, Lng: 143.926495528}
+	distance, err := flindersPeak.VincentyDistance(buninyong, Kilometers)
+	if unexpected(err, t) {
+		return
+	}
+	if math.Abs(distance-54.972271) > 1e-6 {
+		t.Errorf("Expected 54.972271 ~ Received %f", distance)
+	}
+	if distance, err = flindersPeak.VincentyDistance(flindersPeak, Kilometers); err != nil || distance != 0 {
+		t.Errorf("Expected 0 ~ Received %f, %v", distance, err)
+	}
+	if _, err = (LatLng{Lat: 0, Lng: 0}).VincentyDistance(LatLng{Lat: 0.5, Lng: 179.7}, Kilometers); err != ErrVincentyConvergence {
+		t.Errorf("Expected %v ~ Received %v", ErrVincentyConvergence, err)
+	}
+}
+
+func TestBearing(t *testing.T) {
+	origin := LatLng{Lat: 0, Lng: 0}
+	if bearing := origin.Bearing(LatLng{Lat: 0, Lng: 1}); math.Abs(bearing-90) > 1e-9 {
+		t.Errorf("Expected 90 ~ Received %f", bearing)
+	}
+	if bearing := origin.Bearing(LatLng{Lat: -1, Lng: 0}); math.Abs(bearing-180) > 1e-9 {
+		t.Errorf("Expected 180 ~ Received %f", bearing)
+	}
+	// the great circle from London to New York arrives heading south west
+	london, newYork := LatLng{Lat: 51.5007, Lng: -0.1246}, LatLng{Lat: 40.6892, Lng: -74.0445}
+	initial, final := london.Bearing(newYork), london.FinalBearing(newYork)
+	if math.Abs(initial-288.3) > 0.1 || math.Abs(final-231.2) > 0.1 {
+		t.Errorf("Expected 288.3, 231.2 ~ Received %f, %f", initial, final)
+	}
+}
+
+func TestMidpoint(t *testing.T) {
+	midpoint := LatLng{Lat: 0, Lng: 0}.Midpoint(LatLng{Lat: 0, Lng: 90})
+	if math.Abs(midpoint.Lat) > 1e-9 || math.Abs(midpoint.Lng-45) > 1e-9 {
+		t.Errorf("Expected (0, 45) ~ Received %v", midpoint)
+	}
+	midpoint = LatLng{Lat: 10, Lng: 179}.Midpoint(LatLng{Lat: 10, Lng: -179})
+	if math.Abs(math.Abs(midpoint.Lng)-180) > 1e-9 {
+		t.Errorf("Expected lng 180 ~ Received %v", midpoint)
+	}
+}
+
+func TestCrossTrackDistance(t *testing.T) {
+	start, end := LatLng{Lat: 0, Lng: 0}, LatLng{Lat: 0, Lng: 1}
+	degree := earthRadius * math.Pi / 180 / 1000
+	point := LatLng{Lat: 1, Lng: 0.5}
+	if cross := point.CrossTrackDistance(start, end, Kilometers); math.Abs(cross+degree) > 1e-6 {
+		t.Errorf("Expected %f ~ Received %f", -degree, cross)
+	}
+	if along := point.AlongTrackDistance(start, end, Kilometers); math.Abs(along-degree/2) > 0.1 {
+		t.Errorf("Expected %f ~ Received %f", degree/2, along)
+	}
+	if along := (LatLng{Lat: -1, Lng: -0.5}).AlongTrackDistance(start, end, Kilometers); along >= 0 {
+		t.Errorf("Expected a negative distance ~ Received %f", along)
+	}
+}
@@ -0,0 +1,325 @@
+/* Geofencing: evaluates streams of positions against circular and polygon
+fences and emits enter, exit and dwell events.
+
+The fences are kept in a grid index, so only the fences near a position are
+checked. Fences too large for the grid (eg countries) are always checked.
+Events are debounced: a subject has to be seen inside (outside) a fence
+Confirmations times in a row before it enters (exits) it.
+
+Example:
+
+result, err := FullGeocode("Seattle WA")
+engine := NewGeofenceEngine(result.Geofences(500)) // 500 meters around each match
+engine.DwellTime = 10 * time.Minute
+events := engine.Update("truck 12", LatLng{Lat: 47.6062, Lng: -122.3321}, time.Now())
+
+*/
+
+package geocoder
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Geofence is a circle (Center and Radius) or a polygon
+type Geofence struct {
+	ID string
+	// Center and radius (meters) of a circular fence
+	Center LatLng
+	Radius float64
+	// Ring of a polygon fence (closing point optional), used if it has 3 points or more
+	Polygon []LatLng
+}
+
+// NewCircleGeofence is a constructor to initialize a circular fence (radius in meters)
+func NewCircleGeofence(id string, center LatLng, radius float64) Geofence {
+	return Geofence{ID: id, Center: center, Radius: radius}
+}
+
+// NewPolygonGeofence is a constructor to initialize a polygon fence
+func NewPolygonGeofence(id string, polygon []LatLng) Geofence {
+	return Geofence{ID: id, Polygon: polygon}
+}
+
+// Geofences returns a circular fence (radius in meters) around the best match
+// of each result, identified by the provided location. Results without
+// locations are left out.
+func (result GeocodingResult) Geofences(radius float64) []Geofence {
+	var fences []Geofence
+	for _, r := range result.Results {
+		if len(r.Locations) > 0 {
+			fences = append(fences, NewCircleGeofence(r.ProvidedLocation.Location, r.Locations[0].LatLng, radius))
+		}
+	}
+	return fences
+}
+
+// isPolygon reports whether the fence is a polygon
+func (fence Geofence) isPolygon() bool {
+	return len(fence.Polygon) >= 3
+}
+
+// BBox returns the bounding box of the fence
+func (fence Geofence) BBox() BBox {
+	if fence.isPolygon() {
+		return NewBBox(fence.Polygon)
+	}
+	return NewBBox([]LatLng{fence.Center}).Expand(fence.Radius/1000, Kilometers)
+}
+
+// Contains reports whether the point lies within the fence
+func (fence Geofence) Contains(point LatLng) bool {
+	if !fence.isPolygon() {
+		return haversine(fence.Center, point) <= fence.Radius
+	}
+	// ray casting, longitudes relative to the point (polygons narrower than 180°)
+	inside := false
+	polygon := fence.Polygon
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		latI, lngI := polygon[i].Lat, normalizeLng(polygon[i].Lng-point.Lng)
+		latJ, lngJ := polygon[j].Lat, normalizeLng(polygon[j].Lng-point.Lng)
+		if (latI > point.Lat) != (latJ > point.Lat) && 0 < (lngJ-lngI)*(point.Lat-latI)/(latJ-latI)+lngI {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// GeofenceEventType is the kind of a geofence event
+type GeofenceEventType int
+
+// Geofence event types
+const (
+	GeofenceEnter GeofenceEventType = iota
+	GeofenceExit
+	GeofenceDwell
+)
+
+var geofenceEventTypeNames = []string{"Enter", "Exit", "Dwell"}
+
+func (eventType GeofenceEventType) String() string {
+	return enumName(geofenceEventTypeNames, int(eventType), 0, "GeofenceEventType")
+}
+
+// GeofenceEvent reports that a subject entered, exited or dwelled in a fence
+type GeofenceEvent struct {
+	Type GeofenceEventType
+	// Fence ID and subject
+	Fence, Subject string
+	// Position and time of the update which caused the event
+	Position LatLng
+	Time     time.Time
+}
+
+// geofenceState of a subject for a single fence
+type geofenceState struct {
+	inside  bool
+	dwelled bool
+	// time since which the subject is inside
+	since time.Time
+	// number of contrary observations in a row and the time of the first one
+	pending      int
+	pendingSince time.Time
+}
+
+// maximum number of grid cells of a fence, larger fences are kept out of the grid
+const geofenceMaxCells = 1024
+
+// geofenceCell is a cell of the grid index
+type geofenceCell struct {
+	row, column int
+}
+
+// GeofenceEngine evaluates the positions of subjects against fences.
+// A GeofenceEngine is not safe for concurrent use.
+type GeofenceEngine struct {
+	// Number of observations in a row needed to enter or exit a fence (default 1)
+	Confirmations int
+	// Time inside a fence after which a dwell event is emitted (0: no dwell events)
+	DwellTime time.Duration
+	// size in degrees of the cells of the grid index
+	cellSize float64
+	fences   map[string]Geofence
+	grid     map[geofenceCell][]string
+	// IDs of the fences with more than geofenceMaxCells cells
+	large []string
+	// states per subject and fence
+	states map[string]map[string]*geofenceState
+}
+
+// NewGeofenceEngine is a constructor to initialize a GeofenceEngine with fences.
+func NewGeofenceEngine(fences []Geofence) *GeofenceEngine {
+	engine := &GeofenceEngine{
+		Confirmations: 1,
+		cellSize:      0.05,
+		fences:        map[string]Geofence{},
+		grid:          map[geofenceCell][]string{},
+		states:        map[string]map[string]*geofenceState{},
+	}
+	for _, fence := range fences {
+		engine.Add(fence)
+	}
+	return engine
+}
+
+// cell returns the grid cell of a point
+func (engine *GeofenceEngine) cell(point LatLng) geofenceCell {
+	return geofenceCell{
+		row:    int(math.Floor(point.Lat / engine.cellSize)),
+		column: int(math.Floor(normalizeLng(point.Lng) / engine.cellSize)),
+	}
+}
+
+// span returns the upper left and lower right grid cells of the bounding box
+// and the number of columns between them
+func (engine *GeofenceEngine) span(bbox BBox) (upperLeft, lowerRight geofenceCell, width int) {
+	upperLeft, lowerRight = engine.cell(bbox.UpperLeft), engine.cell(bbox.LowerRight)
+	columns := int(math.Round(360 / engine.cellSize))
+	width = lowerRight.column - upperLeft.column
+	if width < 0 || bbox.width() == 360 {
+		// across the antimeridian
+		width += columns
+	}
+	if width >= columns {
+		width = columns - 1
+	}
+	return upperLeft, lowerRight, width
+}
+
+// isLarge reports whether the fence overlaps more than geofenceMaxCells cells
+func (engine *GeofenceEngine) isLarge(fence Geofence) bool {
+	upperLeft, lowerRight, width := engine.span(fence.BBox())
+	return (upperLeft.row-lowerRight.row+1)*(width+1) > geofenceMaxCells
+}
+
+// cells calls fn for each grid cell overlapping the bounding box
+func (engine *GeofenceEngine) cells(bbox BBox, fn func(cell geofenceCell)) {
+	upperLeft, lowerRight, width := engine.span(bbox)
+	columns := int(math.Round(360 / engine.cellSize))
+	for row := lowerRight.row; row <= upperLeft.row; row++ {
+		for c := 0; c <= width; c++ {
+			column := upperLeft.column + c
+			if column >= columns/2 {
+				column -= columns
+			}
+			fn(geofenceCell{row: row, column: column})
+		}
+	}
+}
+
+// Add adds a fence, replacing a fence with the same ID.
+func (engine *GeofenceEngine) Add(fence Geofence) {
+	engine.Remove(fence.ID)
+	engine.fences[fence.ID] = fence
+	if engine.isLarge(fence) {
+		engine.large = append(engine.large, fence.ID)
+		return
+	}
+	engine.cells(fence.BBox(), func(cell geofenceCell) {
+		engine.grid[cell] = append(engine.grid[cell], fence.ID)
+	})
+}
+
+// Remove removes a fence (without exit events)
+func (engine *GeofenceEngine) Remove(id string) {
+	fence, ok := engine.fences[id]
+	if !ok {
+		return
+	}
+	delete(engine.fences, id)
+	for _, states := range engine.states {
+		delete(states, id)
+	}
+	if engine.isLarge(fence) {
+		engine.large = removeID(engine.large, id)
+		return
+	}
+	engine.cells(fence.BBox(), func(cell geofenceCell) {
+		ids := removeID(engine.grid[cell], id)
+		if len(ids) == 0 {
+			delete(engine.grid, cell)
+		} else {
+			engine.grid[cell] = ids
+		}
+	})
+}
+
+// removeID removes the first occurrence of id
+func removeID(ids []string, id string) []string {
+	for i, other := range ids {
+		if other == id {
+			return append(ids[:i], ids[i+1:]...)
+		}
+	}
+	return ids
+}
+
+// Fences returns the IDs of the fences containing the point (sorted)
+func (engine *GeofenceEngine) Fences(point LatLng) []string {
+	var ids []string
+	for _, candidates := range [][]string{engine.grid[engine.cell(point)], engine.large} {
+		for _, id := range candidates {
+			if engine.fences[id].Contains(point) {
+				ids = append(ids, id)
+			}
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Update evaluates the position of a subject at a time and returns the
+// resulting events, ordered by fence ID.
+func (engine *GeofenceEngine) Update(subject string, position LatLng, at time.Time) []GeofenceEvent {
+	states, ok := engine.states[subject]
+	if !ok {
+		states = map[string]*geofenceState{}
+		engine.states[subject] = states
+	}
+	inside := map[string]bool{}
+	for _, id := range engine.Fences(position) {
+		inside[id] = true
+		if _, ok := states[id]; !ok {
+			states[id] = &geofenceState{}
+		}
+	}
+	ids := make([]string, 0, len(states))
+	for id := range states {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	var events []GeofenceEvent
+	emit := func(eventType GeofenceEventType, id string) {
+		events = append(events, GeofenceEvent{Type: eventType, Fence: id, Subject: subject, Position: position, Time: at})
+	}
+	for _, id := range ids {
+		state := states[id]
+		if inside[id] == state.inside {
+			state.pending = 0
+		} else {
+			if state.pending == 0 {
+				state.pendingSince = at
+			}
+			state.pending++
+			if state.pending >= engine.Confirmations {
+				state.inside, state.pending = inside[id], 0
+				if state.inside {
+					state.since, state.dwelled = state.pendingSince, false
+					emit(GeofenceEnter, id)
+				} else {
+					emit(GeofenceExit, id)
+				}
+			}
+		}
+		if state.inside && !state.dwelled && engine.DwellTime > 0 && at.Sub(state.since) >= engine.DwellTime {
+			state.dwelled = true
+			emit(GeofenceDwell, id)
+		}
+		if !state.inside && state.pending == 0 {
+			delete(states, id)
+		}
+	}
+	return events
+}
@@ -0,0 +1,105 @@
+package geocoder
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGeofenceContains(t *testing.T) {
+	circle := NewCircleGeofence("circle", LatLng{}, 1000)
+	if !circle.Contains(destination(LatLng{}, 45, 999)) || circle.Contains(destination(LatLng{}, 45, 1001)) {
+		t.Errorf("Unexpected circle contains")
+	}
+	// a square across the antimeridian
+	square := NewPolygonGeofence("square", []LatLng{{Lat: -1, Lng: 179}, {Lat: -1, Lng: -179}, {Lat: 1, Lng: -179}, {Lat: 1, Lng: 179}})
+	if !square.Contains(LatLng{Lat: 0, Lng: 180}) || !square.Contains(LatLng{Lat: 0.5, Lng: -179.5}) || square.Contains(LatLng{Lat: 0, Lng: 178}) {
+		t.Errorf("Unexpected polygon contains")
+	}
+}
+
+func TestGeofenceEngine(t *testing.T) {
+	var fences []Geofence
+	// a thousand small fences along the equator
+	for i := 0; i < 1000; i++ {
+		fences = append(fences, NewCircleGeofence(fmt.Sprintf("fence %03d", i), LatLng{Lng: float64(i) / 100}, 200))
+	}
+	fences = append(fences, NewPolygonGeofence("square", []LatLng{{Lat: -1, Lng: 179}, {Lat: -1, Lng: -179}, {Lat: 1, Lng: -179}, {Lat: 1, Lng: 179}}))
+	engine := NewGeofenceEngine(fences)
+	if ids := engine.Fences(LatLng{Lng: 5.0001}); !reflect.DeepEqual(ids, []string{"fence 500"}) {
+		t.Errorf("Expected [fence 500] ~ Received %v", ids)
+	}
+	if ids := engine.Fences(LatLng{Lng: -179.99}); !reflect.DeepEqual(ids, []string{"square"}) {
+		t.Errorf("Expected [square] ~ Received %v", ids)
+	}
+	engine.Confirmations = 2
+	engine.DwellTime = 5 * time.Minute
+	start := time.Date(2020, 1, 1, 8, 0, 0, 0, time.UTC)
+	positions := []struct {
+		lng      float64
+		minutes  int
+		expected []GeofenceEventType
+	}{
+		{5.005, 0, nil},
+		{5.0, 1, nil},   // first observation inside
+		{5.005, 2, nil}, // outside again, not confirmed
+		{5.0, 3, nil},   // inside
+		{5.0, 4, []GeofenceEventType{GeofenceEnter}}, // confirmed
+		{5.0, 8, []GeofenceEventType{GeofenceDwell}}, // 5 minutes since 3
+		{5.005, 9, nil}, // outside
+		{5.005, 10, []GeofenceEventType{GeofenceExit}}, // confirmed
+	}
+	for _, p := range positions {
+		events := engine.Update("truck", LatLng{Lng: p.lng}, start.Add(time.Duration(p.minutes)*time.Minute))
+		var received []GeofenceEventType
+		for _, event := range events {
+			received = append(received, event.Type)
+			if event.Fence != "fence 500" || event.Subject != "truck" {
+				t.Errorf("Unexpected event %+v", event)
+			}
+		}
+		if !reflect.DeepEqual(received, p.expected) {
+			t.Errorf("%d minutes: Expected %v ~ Received %v", p.minutes, p.expected, received)
+		}
+	}
+	engine.Remove("fence 500")
+	if ids := engine.Fences(LatLng{Lng: 5.0001}); len(ids) != 0 {
+		t.Errorf("Expected no fences ~ Received %v", ids)
+	}
+}
+
+func TestGeocodingGeofences(t *testing.T) {
+	var result GeocodingResult
+	data := `{"results":[{"providedLocation":{"location":"Seattle WA"},"locations":[{"latLng":{"lat":47.6,"lng":-122.3}}]},
+		{"providedLocation":{"location":"Nowhere"},"locations":[]}]}`
+	if err := json.Unmarshal([]byte(data), &result); unexpected(err, t) {
+		return
+	}
+	expected := []Geofence{NewCircleGeofence("Seattle WA", LatLng{Lat: 47.6, Lng: -122.3}, 500)}
+	if fences := result.Geofences(500); !reflect.DeepEqual(fences, expected) {
+		t.Errorf("Expected %v ~ Received %v", expected, fences)
+	}
+}
+
+func TestGeofenceLargeFences(t *testing.T) {
+	usa := NewPolygonGeofence("usa", []LatLng{{Lat: 24, Lng: -125}, {Lat: 24, Lng: -66}, {Lat: 50, Lng: -66}, {Lat: 50, Lng: -125}})
+	pole := NewCircleGeofence("pole", LatLng{Lat: 89.9, Lng: 0}, 50000)
+	depot := NewCircleGeofence("depot", LatLng{Lat: 47.6, Lng: -122.3}, 500)
+	engine := NewGeofenceEngine([]Geofence{usa, pole, depot})
+	// only the small fence is in the grid
+	if len(engine.grid) > 4 || len(engine.large) != 2 {
+		t.Errorf("Expected 2 large fences and a few cells ~ Received %d, %d cells", len(engine.large), len(engine.grid))
+	}
+	if ids := engine.Fences(LatLng{Lat: 47.6, Lng: -122.3}); len(ids) != 2 || ids[0] != "depot" || ids[1] != "usa" {
+		t.Errorf("Expected [depot usa] ~ Received %v", ids)
+	}
+	if ids := engine.Fences(LatLng{Lat: 89.95, Lng: 120}); len(ids) != 1 || ids[0] != "pole" {
+		t.Errorf("Expected [pole] ~ Received %v", ids)
+	}
+	engine.Remove("usa")
+	if ids := engine.Fences(LatLng{Lat: 40, Lng: -100}); len(ids) != 0 || len(engine.large) != 1 {
+		t.Errorf("Expected no fences ~ Received %v", ids)
+	}
+}
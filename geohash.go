@@ -0,0 +1,184 @@
+/* Geohash encoding, decoding, neighbors and bounding box covers.
+
+A geohash of n characters identifies a cell of the world; nearby points share
+a prefix, which makes geohashes suitable as shard and cache keys
+(see ReverseGeocodeCache).
+
+Example:
+
+hash := LatLng{Lat: 57.64911, Lng: 10.40744}.Geohash(11) // "u4pruydqqvj"
+neighbors, err := GeohashNeighbors(hash)
+hashes := GeohashCover(bbox, 6)
+
+*/
+
+package geocoder
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// maximum geohash precision (characters), about 4 cm
+const geohashMaxPrecision = 12
+
+// Geohash returns the geohash of the point with precision characters (1 to 12).
+func (latLng LatLng) Geohash(precision int) string {
+	return EncodeGeohash(latLng, precision)
+}
+
+// EncodeGeohash returns the geohash of the point with precision characters (1 to 12).
+func EncodeGeohash(point LatLng, precision int) string {
+	if precision < 1 {
+		precision = 1
+	}
+	if precision > geohashMaxPrecision {
+		precision = geohashMaxPrecision
+	}
+	lat := [2]float64{-90, 90}
+	lng := [2]float64{-180, 180}
+	value := [2]float64{point.Lat, normalizeLng(point.Lng)}
+	hash := make([]byte, precision)
+	even := true
+	for i := range hash {
+		var index byte
+		for bit := 0; bit < 5; bit++ {
+			// even bits split the longitudes, odd bits the latitudes
+			interval, v := &lat, value[0]
+			if even {
+				interval, v = &lng, value[1]
+			}
+			mid := (interval[0] + interval[1]) / 2
+			index <<= 1
+			if v >= mid {
+				index |= 1
+				interval[0] = mid
+			} else {
+				interval[1] = mid
+			}
+			even = !even
+		}
+		hash[i] = geohashAlphabet[index]
+	}
+	return string(hash)
+}
+
+// GeohashBBox returns the cell of a geohash
+func GeohashBBox(hash string) (BBox, error) {
+	if hash == "" {
+		return BBox{}, fmt.Errorf("Empty geohash")
+	}
+	lat := [2]float64{-90, 90}
+	lng := [2]float64{-180, 180}
+	even := true
+	for i, c := range strings.ToLower(hash) {
+		index := strings.IndexRune(geohashAlphabet, c)
+		if index < 0 {
+			return BBox{}, fmt.Errorf("Invalid geohash character %q at position %d", c, i)
+		}
+		for bit := 4; bit >= 0; bit-- {
+			interval := &lat
+			if even {
+				interval = &lng
+			}
+			mid := (interval[0] + interval[1]) / 2
+			if index>>uint(bit)&1 == 1 {
+				interval[0] = mid
+			} else {
+				interval[1] = mid
+			}
+			even = !even
+		}
+	}
+	return BBox{UpperLeft: LatLng{Lat: lat[1], Lng: lng[0]}, LowerRight: LatLng{Lat: lat[0], Lng: lng[1]}}, nil
+}
+
+// DecodeGeohash returns the center of the cell of a geohash
+func DecodeGeohash(hash string) (LatLng, error) {
+	bbox, err := GeohashBBox(hash)
+	if err != nil {
+		return LatLng{}, err
+	}
+	return LatLng{
+		Lat: (bbox.UpperLeft.Lat + bbox.LowerRight.Lat) / 2,
+		Lng: (bbox.UpperLeft.Lng + bbox.LowerRight.Lng) / 2,
+	}, nil
+}
+
+// GeohashNeighbor returns the adjacent geohash (of the same precision) in a
+// compass direction. Neighbors beyond the poles do not exist (empty string).
+func GeohashNeighbor(hash string, direction Compass) (string, error) {
+	bbox, err := GeohashBBox(hash)
+	if err != nil {
+		return "", err
+	}
+	height := bbox.UpperLeft.Lat - bbox.LowerRight.Lat
+	width := bbox.LowerRight.Lng - bbox.UpperLeft.Lng
+	center := LatLng{Lat: bbox.LowerRight.Lat + height/2, Lng: bbox.UpperLeft.Lng + width/2}
+	switch direction {
+	case CompassNorth, CompassNorthwest, CompassNortheast:
+		center.Lat += height
+	case CompassSouth, CompassSouthwest, CompassSoutheast:
+		center.Lat -= height
+	}
+	switch direction {
+	case CompassEast, CompassNortheast, CompassSoutheast:
+		center.Lng += width
+	case CompassWest, CompassNorthwest, CompassSouthwest:
+		center.Lng -= width
+	}
+	if center.Lat > 90 || center.Lat < -90 {
+		return "", nil
+	}
+	return EncodeGeohash(center, len(hash)), nil
+}
+
+// GeohashNeighbors returns the 8 adjacent geohashes by compass direction,
+// leaving out neighbors beyond the poles.
+func GeohashNeighbors(hash string) (map[Compass]string, error) {
+	neighbors := map[Compass]string{}
+	for direction := CompassNorth; direction <= CompassEast; direction++ {
+		neighbor, err := GeohashNeighbor(hash, direction)
+		if err != nil {
+			return nil, err
+		}
+		if neighbor != "" {
+			neighbors[direction] = neighbor
+		}
+	}
+	return neighbors, nil
+}
+
+// GeohashCover returns the geohashes (of precision characters) of all cells
+// intersecting the bounding box, from south west to north east.
+// The number of cells grows quickly with the precision.
+func GeohashCover(bbox BBox, precision int) []string {
+	cell, _ := GeohashBBox(EncodeGeohash(bbox.LowerRight, precision))
+	height := cell.UpperLeft.Lat - cell.LowerRight.Lat
+	width := cell.LowerRight.Lng - cell.UpperLeft.Lng
+	// cells from the west edge, eastwards over the width of the box
+	columns := int(math.Ceil(bbox.width()/width)) + 1
+	if maxColumns := int(math.Round(360 / width)); columns > maxColumns {
+		columns = maxColumns
+	}
+	var hashes []string
+	seen := map[string]bool{}
+	for lat := bbox.LowerRight.Lat; ; lat += height {
+		row := math.Min(lat, bbox.UpperLeft.Lat)
+		for c := 0; c < columns; c++ {
+			lng := math.Min(bbox.UpperLeft.Lng+float64(c)*width, bbox.UpperLeft.Lng+bbox.width())
+			hash := EncodeGeohash(LatLng{Lat: row, Lng: lng}, precision)
+			if !seen[hash] {
+				seen[hash] = true
+				hashes = append(hashes, hash)
+			}
+		}
+		if lat >= bbox.UpperLeft.Lat {
+			break
+		}
+	}
+	return hashes
+}
@@ -0,0 +1,82 @@
+package geocoder
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGeohash(t *testing.T) {
+	point := LatLng{Lat: 57.64911, Lng: 10.40744}
+	if hash := point.Geohash(11); hash != "u4pruydqqvj" {
+		t.Errorf("Expected u4pruydqqvj ~ Received %v", hash)
+	}
+	if hash := EncodeGeohash(point, 20); len(hash) != 12 {
+		t.Errorf("Expected 12 characters ~ Received %v", hash)
+	}
+	decoded, err := DecodeGeohash("u4pruydqqvj")
+	if unexpected(err, t) {
+		return
+	}
+	if math.Abs(decoded.Lat-point.Lat) > 1e-5 || math.Abs(decoded.Lng-point.Lng) > 1e-5 {
+		t.Errorf("Expected %v ~ Received %v", point, decoded)
+	}
+	bbox, err := GeohashBBox("u4pru")
+	if unexpected(err, t) {
+		return
+	}
+	if !bbox.Contains(point) {
+		t.Errorf("Expected %v to contain %v", bbox, point)
+	}
+	if _, err := DecodeGeohash("u4pa"); err == nil {
+		t.Errorf("Expected an error for an invalid character")
+	}
+	if _, err := DecodeGeohash(""); err == nil {
+		t.Errorf("Expected an error for an empty geohash")
+	}
+}
+
+func TestGeohashNeighbors(t *testing.T) {
+	neighbors, err := GeohashNeighbors("gbsuv")
+	if unexpected(err, t) {
+		return
+	}
+	expected := map[Compass]string{
+		CompassNorth: "gbsvj", CompassEast: "gbsuy", CompassSouth: "gbsut", CompassWest: "gbsuu",
+		CompassNortheast: "gbsvn", CompassSoutheast: "gbsuw", CompassSouthwest: "gbsus", CompassNorthwest: "gbsvh",
+	}
+	for direction, hash := range expected {
+		if neighbors[direction] != hash {
+			t.Errorf("%v: Expected %v ~ Received %v", direction, hash, neighbors[direction])
+		}
+	}
+	// across the antimeridian and beyond the pole
+	if east, _ := GeohashNeighbor("z", CompassEast); east != "b" {
+		t.Errorf("Expected b ~ Received %v", east)
+	}
+	neighbors, _ = GeohashNeighbors("z")
+	if len(neighbors) != 5 {
+		t.Errorf("Expected 5 neighbors ~ Received %v", neighbors)
+	}
+}
+
+func TestGeohashCover(t *testing.T) {
+	cell, _ := GeohashBBox("gbsuv")
+	hashes := GeohashCover(cell, 5)
+	if len(hashes) > 4 || hashes[0] != "gbsuv" {
+		t.Errorf("Expected gbsuv ~ Received %v", hashes)
+	}
+	bbox := BBox{UpperLeft: LatLng{Lat: 10, Lng: 170}, LowerRight: LatLng{Lat: -10, Lng: -170}}
+	hashes = GeohashCover(bbox, 2)
+	covered := map[string]bool{}
+	for _, hash := range hashes {
+		covered[hash] = true
+	}
+	for _, point := range []LatLng{{Lat: 0, Lng: 179.9}, {Lat: 9, Lng: -171}, {Lat: -9, Lng: 171}, {Lat: 10, Lng: -170}} {
+		if !covered[point.Geohash(2)] {
+			t.Errorf("Expected %v to be covered by %v", point, hashes)
+		}
+	}
+	if covered[LatLng{Lat: 0, Lng: 0}.Geohash(2)] {
+		t.Errorf("Unexpected cover %v", hashes)
+	}
+}
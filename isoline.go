@@ -0,0 +1,180 @@
+/* Calculates isolines (drive time or drive distance polygons).
+
+The polygon is computed by sampling destinations on rings around the center
+in a number of directions, measuring them with a single one to many route
+matrix and interpolating, per direction, the point where the budget runs out.
+
+Example:
+
+isoline := NewTimeIsoline(LatLng{Lat: 51.22111, Lng: 4.399708}, 20*60) // 20 minutes
+result, err := isoline.Get()
+geojson, err := result.GeoJSON()
+
+*/
+
+package geocoder
+
+import "encoding/json"
+
+// Isoline describes the area reachable from a center within a time
+// or distance budget.
+type Isoline struct {
+	// Center of the isoline
+	Center LatLng
+	// Time budget in seconds (used if positive)
+	Time int
+	// Distance budget in Unit (used if Time is 0)
+	Distance float64
+	// type of units of Distance: m (Miles, default) or k (Km)
+	Unit Unit
+	// fastest(default), shortest, pedestrian, bicycle
+	RouteType RouteType
+	// Number of directions to sample (default 16)
+	Directions int
+	// Number of rings to sample per direction (default 5)
+	Rings int
+}
+
+// NewTimeIsoline is a constructor to initialize an Isoline of the area
+// reachable within a number of seconds.
+func NewTimeIsoline(center LatLng, seconds int) *Isoline {
+	return &Isoline{
+		Center:     center,
+		Time:       seconds,
+		Unit:       Miles,
+		RouteType:  Fastest,
+		Directions: 16,
+		Rings:      5,
+	}
+}
+
+// NewDistanceIsoline is a constructor to initialize an Isoline of the area
+// reachable within a route distance (in unit).
+func NewDistanceIsoline(center LatLng, distance float64, unit Unit) *Isoline {
+	isoline := NewTimeIsoline(center, 0)
+	isoline.Distance = distance
+	isoline.Unit = unit
+	return isoline
+}
+
+// IsolineResult holds the polygon of an isoline
+type IsolineResult struct {
+	// Closed ring of points ordered by bearing from the center
+	Polygon []LatLng
+}
+
+// Validate checks the isoline options before any request is made.
+// All invalid options are reported together as ValidationErrors.
+func (isoline Isoline) Validate() error {
+	v := &validator{}
+	isoline.Center.Normalize().validate(v, "Center")
+	v.check(isoline.Time > 0 || isoline.Distance > 0, "Time or Distance must be positive")
+	isoline.Unit.validate(v)
+	v.oneOf("RouteType", string(isoline.RouteType),
+		string(Fastest), string(Shortest), string(Pedestrian), string(Bicycle))
+	v.check(isoline.Directions >= 3, "Directions %d is less than 3", isoline.Directions)
+	v.check(isoline.Rings >= 1, "Rings %d is less than 1", isoline.Rings)
+	v.check(isoline.Directions*isoline.Rings < matrixOneToManyLimit,
+		"Directions x Rings (%d) exceeds %d samples", isoline.Directions*isoline.Rings, matrixOneToManyLimit-1)
+	return v.err()
+}
+
+// radius estimates the straight line radius (meters) beyond which
+// the budget can not be reached.
+func (isoline Isoline) radius() float64 {
+	if isoline.Time <= 0 {
+		// a route is never shorter than a straight line
+		return isoline.Distance * isoline.Unit.meters()
+	}
+	speed := 35.0 // meters per second, fast highway traffic
+	switch isoline.RouteType {
+	case Pedestrian:
+		speed = 2
+	case Bicycle:
+		speed = 8
+	}
+	return float64(isoline.Time) * speed
+}
+
+// Get samples the destinations with a route matrix and builds the polygon.
+func (isoline Isoline) Get() (*IsolineResult, error) {
+	if err := isoline.Validate(); err != nil {
+		return nil, err
+	}
+	radius := isoline.radius()
+	radii := make([]float64, isoline.Rings)
+	for r := range radii {
+		radii[r] = radius * float64(r+1) / float64(isoline.Rings)
+	}
+	waypoints := []Waypoint{NewLatLngWaypoint(isoline.Center)}
+	for d := 0; d < isoline.Directions; d++ {
+		bearing := 360 * float64(d) / float64(isoline.Directions)
+		for _, r := range radii {
+			waypoints = append(waypoints, NewLatLngWaypoint(destination(isoline.Center, bearing, r)))
+		}
+	}
+	matrix := NewRouteMatrix(waypoints)
+	matrix.AllToAll = false
+	matrix.Unit = isoline.Unit
+	matrix.RouteType = isoline.RouteType
+	results, err := matrix.Get()
+	if err != nil {
+		return nil, err
+	}
+	costs := make([][]float64, isoline.Directions)
+	budget := isoline.Distance
+	for d := range costs {
+		costs[d] = make([]float64, isoline.Rings)
+		for r := range costs[d] {
+			j := 1 + d*isoline.Rings + r
+			if isoline.Time > 0 {
+				costs[d][r] = float64(results.Time[0][j])
+			} else {
+				costs[d][r] = results.Distance[0][j]
+			}
+		}
+	}
+	if isoline.Time > 0 {
+		budget = float64(isoline.Time)
+	}
+	return &IsolineResult{Polygon: isolinePolygon(isoline.Center, radii, costs, budget)}, nil
+}
+
+// isolinePolygon interpolates per direction the radius at which the cost
+// reaches the budget. costs[d][r] is the cost to reach radii[r] in
+// direction d (evenly spread bearings); a cost of 0 or less is unreachable.
+func isolinePolygon(center LatLng, radii []float64, costs [][]float64, budget float64) []LatLng {
+	polygon := make([]LatLng, 0, len(costs)+1)
+	for d, directionCosts := range costs {
+		// the center is reached at no cost
+		reach, previousRadius, previousCost := 0.0, 0.0, 0.0
+		for r, cost := range directionCosts {
+			if cost <= 0 || cost > budget {
+				if cost > budget {
+					reach = previousRadius + (radii[r]-previousRadius)*(budget-previousCost)/(cost-previousCost)
+				}
+				break
+			}
+			reach, previousRadius, previousCost = radii[r], radii[r], cost
+		}
+		bearing := 360 * float64(d) / float64(len(costs))
+		polygon = append(polygon, destination(center, bearing, reach))
+	}
+	if len(polygon) > 0 {
+		polygon = append(polygon, polygon[0])
+	}
+	return polygon
+}
+
+// FeatureCollection returns the isoline as a GeoJSON Polygon
+func (result IsolineResult) FeatureCollection() FeatureCollection {
+	return FeatureCollection{
+		Type:     "FeatureCollection",
+		Features: []Feature{NewPolygonFeature(result.Polygon, map[string]interface{}{"name": "isoline"})},
+	}
+}
+
+// GeoJSON encodes the isoline as a GeoJSON FeatureCollection
+func (result IsolineResult) GeoJSON() ([]byte, error) {
+	return json.Marshal(result.FeatureCollection())
+}
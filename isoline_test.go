@@ -0,0 +1,63 @@
+package geocoder
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIsolinePolygon(t *testing.T) {
+	center := LatLng{Lat: 51.22111, Lng: 4.399708}
+	radii := []float64{1000, 2000, 3000, 4000}
+	costs := [][]float64{
+		{100, 200, 300, 400}, // budget reached between 2000 and 3000 meters
+		{100, 200, 300, 400},
+		{300, 600, 900, 1200}, // budget reached before 1000 meters
+		{10, 20, 30, 40},      // budget never reached: outer ring
+		{100, -1, 300, 400},   // unreachable sample
+	}
+	polygon := isolinePolygon(center, radii, costs, 250)
+	if len(polygon) != len(costs)+1 || polygon[0] != polygon[len(polygon)-1] {
+		t.Fatalf("Expected a closed ring of %d points ~ Received %v", len(costs)+1, polygon)
+	}
+	for d, expected := range []float64{2500, 2500, 250.0 / 300 * 1000, 4000, 1000} {
+		bearing := 360 * float64(d) / float64(len(costs))
+		if point := destination(center, bearing, expected); math.Abs(point.Lat-polygon[d].Lat) > 1e-9 || math.Abs(point.Lng-polygon[d].Lng) > 1e-9 {
+			t.Errorf("Direction %d: Expected %v ~ Received %v", d, point, polygon[d])
+		}
+	}
+	collection := IsolineResult{Polygon: polygon}.FeatureCollection()
+	if collection.Features[0].Geometry.Type != "Polygon" {
+		t.Errorf("Expected Polygon ~ Received %s", collection.Features[0].Geometry.Type)
+	}
+}
+
+func TestIsolineGet(t *testing.T) {
+	saved := postRouteMatrix
+	defer func() { postRouteMatrix = saved }()
+	requests := 0
+	postRouteMatrix = func(body matrixBody) (*matrixResponse, error) {
+		requests++
+		if body.Options.AllToAll || len(body.Locations) != 81 {
+			t.Errorf("Expected a single one to many request of 81 locations ~ Received %d", len(body.Locations))
+		}
+		// every ring takes 5 minutes more
+		row := make([]float64, len(body.Locations))
+		for j := 1; j < len(row); j++ {
+			row[j] = float64(300 * ((j-1)%5 + 1))
+		}
+		return &matrixResponse{Distance: matrixRows{row}, Time: matrixRows{row}}, nil
+	}
+	isoline := NewTimeIsoline(LatLng{Lat: 51.22111, Lng: 4.399708}, 20*60)
+	result, err := isoline.Get()
+	if unexpected(err, t) {
+		return
+	}
+	if requests != 1 || len(result.Polygon) != 17 {
+		t.Errorf("Expected 1 request and 17 points ~ Received %d, %d", requests, len(result.Polygon))
+	}
+	// 20 minutes is reached at the 4th of 5 rings
+	expected := destination(isoline.Center, 0, isoline.radius()*4/5)
+	if math.Abs(result.Polygon[0].Lat-expected.Lat) > 1e-9 {
+		t.Errorf("Expected %v ~ Received %v", expected, result.Polygon[0])
+	}
+}
@@ -0,0 +1,94 @@
+/* Validation and normalization of LatLng values.
+
+Points are validated (and their longitudes normalized) before any request
+is sent: ReverseGeocode, the waypoints of Directions and RouteMatrix and the
+center of an Isoline.
+
+Example:
+
+point := LatLng{Lat: 47.6062, Lng: 237.6679}.Normalize() // Lng: -122.3321
+if err := point.Validate(); err != nil {
+	// NaN, out of range or swapped
+}
+rounded := point.RoundMeters(10) // as few decimals as needed for 10 meters
+
+*/
+
+package geocoder
+
+import (
+	"fmt"
+	"math"
+)
+
+// length of a degree of latitude in meters
+const metersPerDegree = math.Pi / 180 * earthRadius
+
+// Validate checks that the point has finite coordinates within range:
+// latitudes within [-90, 90] and longitudes within [-180, 180] (see Normalize).
+func (latLng LatLng) Validate() error {
+	switch {
+	case math.IsNaN(latLng.Lat) || math.IsInf(latLng.Lat, 0) || math.IsNaN(latLng.Lng) || math.IsInf(latLng.Lng, 0):
+		return fmt.Errorf("Invalid coordinates %v", latLng)
+	case latLng.LikelySwapped():
+		return fmt.Errorf("Latitude %v out of range [-90, 90], latitude and longitude look swapped", latLng.Lat)
+	case math.Abs(latLng.Lat) > 90:
+		return fmt.Errorf("Latitude %v out of range [-90, 90]", latLng.Lat)
+	case math.Abs(latLng.Lng) > 180:
+		return fmt.Errorf("Longitude %v out of range [-180, 180], see Normalize", latLng.Lng)
+	}
+	return nil
+}
+
+// validate adds the error of an invalid point
+func (latLng LatLng) validate(v *validator, name string) {
+	err := latLng.Validate()
+	v.check(err == nil, "%s: %v", name, err)
+}
+
+// Normalize returns the point with its longitude wrapped into [-180, 180],
+// eg 190 becomes -170. Latitudes are left as is (see Validate).
+func (latLng LatLng) Normalize() LatLng {
+	if latLng.Lng < -180 || latLng.Lng > 180 {
+		latLng.Lng = normalizeLng(latLng.Lng)
+	}
+	return latLng
+}
+
+// Round returns the point rounded to a number of decimals
+func (latLng LatLng) Round(decimals int) LatLng {
+	scale := math.Pow10(decimals)
+	return LatLng{Lat: math.Round(latLng.Lat*scale) / scale, Lng: math.Round(latLng.Lng*scale) / scale}
+}
+
+// RoundMeters returns the point rounded to as few decimals as needed for a
+// precision in meters. Longitudes need fewer decimals away from the equator.
+func (latLng LatLng) RoundMeters(precision float64) LatLng {
+	decimals := func(metersPerDegree float64) int {
+		if precision <= 0 || metersPerDegree <= 0 {
+			return 15
+		}
+		return int(math.Max(0, math.Ceil(math.Log10(metersPerDegree/precision))))
+	}
+	lat := latLng.Round(decimals(metersPerDegree)).Lat
+	lng := latLng.Round(decimals(metersPerDegree * math.Cos(radians(latLng.Lat)))).Lng
+	return LatLng{Lat: lat, Lng: lng}
+}
+
+// Swap returns the point with latitude and longitude exchanged
+func (latLng LatLng) Swap() LatLng {
+	return LatLng{Lat: latLng.Lng, Lng: latLng.Lat}
+}
+
+// LikelySwapped reports whether latitude and longitude look exchanged:
+// the latitude is out of range, while the swapped point is valid.
+func (latLng LatLng) LikelySwapped() bool {
+	return math.Abs(latLng.Lat) > 90 && math.Abs(latLng.Lat) <= 180 && math.Abs(latLng.Lng) <= 90
+}
+
+// LikelySwappedWithin reports whether latitude and longitude look exchanged
+// for a point expected in a region: the point lies outside of the bounding
+// box, while the swapped point lies inside.
+func (latLng LatLng) LikelySwappedWithin(bbox BBox) bool {
+	return latLng.LikelySwapped() || !bbox.Contains(latLng) && bbox.Contains(latLng.Swap())
+}
@@ -0,0 +1,81 @@
+package geocoder
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestLatLngValidate(t *testing.T) {
+	for _, point := range []LatLng{{Lat: 47.6062, Lng: -122.3321}, {Lat: -90, Lng: 180}, {Lat: 90, Lng: -180}} {
+		unexpected(point.Validate(), t)
+	}
+	for _, point := range []LatLng{{Lat: math.NaN(), Lng: 0}, {Lat: 0, Lng: math.Inf(1)}, {Lat: 91, Lng: 0}, {Lat: 0, Lng: 181}, {Lat: 200, Lng: 10}} {
+		if err := point.Validate(); err == nil {
+			t.Errorf("%v: Expected an error", point)
+		}
+	}
+	if err := (LatLng{Lat: -122.3321, Lng: 47.6062}).Validate(); err == nil || !strings.Contains(err.Error(), "swapped") {
+		t.Errorf("Expected a swapped error ~ Received %v", err)
+	}
+}
+
+func TestLatLngNormalize(t *testing.T) {
+	cases := []struct{ lng, expected float64 }{{190, -170}, {-190, 170}, {540, 180}, {180, 180}, {-180, -180}, {-122.5, -122.5}, {237.5, -122.5}}
+	for _, c := range cases {
+		if lng := (LatLng{Lat: 10, Lng: c.lng}).Normalize().Lng; math.Abs(lng-c.expected) > 1e-9 && math.Abs(math.Abs(lng-c.expected)-360) > 1e-9 {
+			t.Errorf("%v: Expected %v ~ Received %v", c.lng, c.expected, lng)
+		}
+	}
+}
+
+func TestLatLngRound(t *testing.T) {
+	point := LatLng{Lat: 47.60621234, Lng: -122.33207891}
+	if rounded := point.Round(3); rounded != (LatLng{Lat: 47.606, Lng: -122.332}) {
+		t.Errorf("Expected (47.606, -122.332) ~ Received %v", rounded)
+	}
+	// 10 meters: 5 decimals of latitude, 4 of longitude at 47° (75 km per degree)
+	if rounded := point.RoundMeters(10); rounded != (LatLng{Lat: 47.60621, Lng: -122.3321}) {
+		t.Errorf("Expected (47.60621, -122.3321) ~ Received %v", rounded)
+	}
+	// 1 km near the pole: a single decimal of longitude is enough
+	if rounded := (LatLng{Lat: 89.55555, Lng: 45.55555}).RoundMeters(1000); rounded != (LatLng{Lat: 89.556, Lng: 46}) {
+		t.Errorf("Expected (89.556, 46) ~ Received %v", rounded)
+	}
+}
+
+func TestLatLngSwapped(t *testing.T) {
+	if !(LatLng{Lat: -122.3321, Lng: 47.6062}).LikelySwapped() || (LatLng{Lat: 47.6062, Lng: -122.3321}).LikelySwapped() {
+		t.Errorf("Unexpected swapped detection")
+	}
+	// both orders are valid, but only one lies in Belgium
+	belgium := BBox{UpperLeft: LatLng{Lat: 51.5, Lng: 2.5}, LowerRight: LatLng{Lat: 49.5, Lng: 6.4}}
+	antwerp := LatLng{Lat: 51.22111, Lng: 4.399708}
+	if antwerp.LikelySwappedWithin(belgium) || !antwerp.Swap().LikelySwappedWithin(belgium) {
+		t.Errorf("Unexpected swapped detection within %v", belgium)
+	}
+}
+
+func TestLatLngBeforeRequests(t *testing.T) {
+	// rejected without a request
+	if _, err := ReverseGeocode(math.NaN(), 4.4); err == nil {
+		t.Errorf("Expected an error for NaN")
+	}
+	matrix := NewRouteMatrix([]Waypoint{NewLatLngWaypoint(LatLng{Lat: 95, Lng: 4.4}), NewLatLngWaypoint(LatLng{Lat: 51, Lng: 364.4})})
+	err := matrix.Validate()
+	if err == nil || !strings.Contains(err.Error(), "Locations[0].LatLng") || strings.Contains(err.Error(), "Locations[1]") {
+		t.Errorf("Expected an error for Locations[0] only ~ Received %v", err)
+	}
+	// longitudes are normalized in the request body
+	body := matrix.Locations[1].body().(locationBody)
+	if math.Abs(body.LatLng.Lng-4.4) > 1e-9 {
+		t.Errorf("Expected 4.4 ~ Received %v", body.LatLng.Lng)
+	}
+	if !strings.Contains(reverseGeocodeRequestURL(LatLng{Lat: 51.2211123456, Lng: 4.3997}), "location=51.221112,4.3997&") {
+		t.Errorf("Unexpected url %v", reverseGeocodeRequestURL(LatLng{Lat: 51.2211123456, Lng: 4.3997}))
+	}
+	isoline := NewTimeIsoline(LatLng{Lat: math.NaN()}, 600)
+	if err := isoline.Validate(); err == nil || !strings.Contains(err.Error(), "Center") {
+		t.Errorf("Expected a Center error ~ Received %v", err)
+	}
+}
@@ -0,0 +1,177 @@
+package geocoder
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ManeuverAttribute flags describe the roads of a maneuver
+type ManeuverAttribute int
+
+// Maneuver attributes
+const (
+	AttributeNone            ManeuverAttribute = 0
+	AttributeToll            ManeuverAttribute = 1
+	AttributeUnpaved         ManeuverAttribute = 2
+	AttributeSeasonalClosure ManeuverAttribute = 4
+	AttributeGate            ManeuverAttribute = 8
+	AttributeFerry           ManeuverAttribute = 16
+	AttributeAvoidID         ManeuverAttribute = 32
+	AttributeCountryCrossing ManeuverAttribute = 64
+	AttributeLimitedAccess   ManeuverAttribute = 128
+)
+
+var maneuverAttributeNames = []string{
+	"Toll", "Unpaved", "SeasonalClosure", "Gate", "Ferry", "AvoidID", "CountryCrossing", "LimitedAccess",
+}
+
+// Has reports whether all attributes of flag are set
+func (attributes ManeuverAttribute) Has(flag ManeuverAttribute) bool {
+	return attributes&flag == flag
+}
+
+// String joins the names of the set attributes, eg "Toll|Ferry"
+func (attributes ManeuverAttribute) String() string {
+	if attributes == AttributeNone {
+		return "None"
+	}
+	var names []string
+	for i, name := range maneuverAttributeNames {
+		if attributes.Has(1 << uint(i)) {
+			names = append(names, name)
+		}
+	}
+	if unknown := attributes &^ (1<<uint(len(maneuverAttributeNames)) - 1); unknown != 0 {
+		names = append(names, fmt.Sprintf("%d", int(unknown)))
+	}
+	return strings.Join(names, "|")
+}
+
+// UnmarshalJSON decodes a number (as mapquest does) or names such as "Toll|Ferry"
+func (attributes *ManeuverAttribute) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return json.Unmarshal(data, (*int)(attributes))
+	}
+	*attributes = AttributeNone
+	if name == "None" || name == "" {
+		return nil
+	}
+	for _, part := range strings.Split(name, "|") {
+		flag, ok := lookupName(maneuverAttributeNames, part)
+		if !ok {
+			return fmt.Errorf("Unknown maneuver attribute %q", part)
+		}
+		*attributes |= 1 << uint(flag)
+	}
+	return nil
+}
+
+// Compass direction of a maneuver or sign
+type Compass int
+
+// Compass directions
+const (
+	CompassNone      Compass = 0
+	CompassNorth     Compass = 1
+	CompassNorthwest Compass = 2
+	CompassNortheast Compass = 3
+	CompassSouth     Compass = 4
+	CompassSoutheast Compass = 5
+	CompassSouthwest Compass = 6
+	CompassWest      Compass = 7
+	CompassEast      Compass = 8
+)
+
+var compassNames = []string{
+	"None", "North", "Northwest", "Northeast", "South", "Southeast", "Southwest", "West", "East",
+}
+
+func (compass Compass) String() string {
+	return enumName(compassNames, int(compass), 0, "Compass")
+}
+
+// UnmarshalJSON decodes a number (as mapquest does) or a name such as "North"
+func (compass *Compass) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, compassNames, 0, "compass direction", (*int)(compass))
+}
+
+// TurnType of a maneuver
+type TurnType int
+
+// Turn types
+const (
+	TurnNone         TurnType = -1
+	TurnStraight     TurnType = 0
+	TurnSlightRight  TurnType = 1
+	TurnRight        TurnType = 2
+	TurnSharpRight   TurnType = 3
+	TurnReverse      TurnType = 4
+	TurnSharpLeft    TurnType = 5
+	TurnLeft         TurnType = 6
+	TurnSlightLeft   TurnType = 7
+	TurnRightUTurn   TurnType = 8
+	TurnLeftUTurn    TurnType = 9
+	TurnRightMerge   TurnType = 10
+	TurnLeftMerge    TurnType = 11
+	TurnRightOnRamp  TurnType = 12
+	TurnLeftOnRamp   TurnType = 13
+	TurnRightOffRamp TurnType = 14
+	TurnLeftOffRamp  TurnType = 15
+	TurnRightFork    TurnType = 16
+	TurnLeftFork     TurnType = 17
+	TurnStraightFork TurnType = 18
+)
+
+// turn type names starting at TurnNone
+var turnTypeNames = []string{
+	"None", "Straight", "SlightRight", "Right", "SharpRight", "Reverse", "SharpLeft", "Left", "SlightLeft",
+	"RightUTurn", "LeftUTurn", "RightMerge", "LeftMerge", "RightOnRamp", "LeftOnRamp",
+	"RightOffRamp", "LeftOffRamp", "RightFork", "LeftFork", "StraightFork",
+}
+
+func (turnType TurnType) String() string {
+	return enumName(turnTypeNames, int(turnType), int(TurnNone), "TurnType")
+}
+
+// UnmarshalJSON decodes a number (as mapquest does) or a name such as "SlightLeft"
+func (turnType *TurnType) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, turnTypeNames, int(TurnNone), "turn type", (*int)(turnType))
+}
+
+// enumName returns the name of value, names start at first
+func enumName(names []string, value, first int, kind string) string {
+	if i := value - first; i >= 0 && i < len(names) {
+		return names[i]
+	}
+	return fmt.Sprintf("%s(%d)", kind, value)
+}
+
+// lookupName returns the index of name (case insensitive)
+func lookupName(names []string, name string) (int, bool) {
+	for i, n := range names {
+		if strings.EqualFold(n, name) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// unmarshalEnum decodes a number or a name (names start at first) into value.
+// A json null leaves value unchanged.
+func unmarshalEnum(data []byte, names []string, first int, kind string, value *int) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return json.Unmarshal(data, value)
+	}
+	i, ok := lookupName(names, name)
+	if !ok {
+		return fmt.Errorf("Unknown %s %q", kind, name)
+	}
+	*value = first + i
+	return nil
+}
@@ -0,0 +1,57 @@
+package geocoder
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestManeuverAttribute(t *testing.T) {
+	attributes := AttributeToll | AttributeFerry
+	if !attributes.Has(AttributeToll) || !attributes.Has(AttributeFerry) || attributes.Has(AttributeUnpaved) {
+		t.Errorf("Unexpected flags of %d", attributes)
+	}
+	if attributes.String() != "Toll|Ferry" || AttributeNone.String() != "None" {
+		t.Errorf("Expected Toll|Ferry ~ Received %s", attributes)
+	}
+	var decoded ManeuverAttribute
+	if err := json.Unmarshal([]byte(`"Toll|LimitedAccess"`), &decoded); unexpected(err, t) {
+		return
+	}
+	if decoded != 129 {
+		t.Errorf("Expected 129 ~ Received %d", decoded)
+	}
+}
+
+func TestManeuverEnums(t *testing.T) {
+	var maneuver Maneuver
+	err := json.Unmarshal([]byte(`{"direction":7,"turnType":-1,"attributes":16,"signs":[{"direction":"northeast"}]}`), &maneuver)
+	if unexpected(err, t) {
+		return
+	}
+	if maneuver.Direction != CompassWest || maneuver.Direction.String() != "West" {
+		t.Errorf("Expected West ~ Received %s", maneuver.Direction)
+	}
+	if maneuver.TurnType != TurnNone || TurnSlightLeft.String() != "SlightLeft" || TurnType(42).String() != "TurnType(42)" {
+		t.Errorf("Unexpected turn types %s, %s", maneuver.TurnType, TurnType(42))
+	}
+	if !maneuver.Attributes.Has(AttributeFerry) || maneuver.Signs[0].Direction != CompassNortheast {
+		t.Errorf("Unexpected maneuver %+v", maneuver)
+	}
+	data, err := json.Marshal(TurnRightFork)
+	if unexpected(err, t) {
+		return
+	}
+	if string(data) != "16" {
+		t.Errorf("Expected the mapquest number 16 ~ Received %s", data)
+	}
+	if err = json.Unmarshal([]byte(`"sideways"`), &maneuver.TurnType); err == nil {
+		t.Errorf("Expected error for unknown turn type")
+	}
+	// null is ignored like it was for the plain int fields
+	if err = json.Unmarshal([]byte(`{"turnType":null,"direction":null}`), &maneuver); unexpected(err, t) {
+		return
+	}
+	if maneuver.TurnType != TurnNone || maneuver.Direction != CompassWest {
+		t.Errorf("Expected None and West ~ Received %s and %s", maneuver.TurnType, maneuver.Direction)
+	}
+}
@@ -0,0 +1,261 @@
+/* Exposes the mapquest route matrix api.
+
+Reference: http://open.mapquestapi.com/directions/#matrix
+
+Example:
+
+matrix := NewRouteMatrix(AddressWaypoints([]string{"Amsterdam,Netherlands", "Antwerp,Belgium", "Brussels,Belgium"}))
+matrix.Unit = Kilometers
+results, err := matrix.Get()
+distance := results.Distance[0][2] // Amsterdam to Brussels
+
+*/
+
+package geocoder
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+)
+
+const (
+	// maximum number of locations of a single all to all request
+	matrixAllToAllLimit = 25
+	// maximum number of locations of a single one to many (or many to one) request
+	matrixOneToManyLimit = 100
+)
+
+// RouteMatrix calculates the distances and times between many locations
+// with as few requests as possible.
+type RouteMatrix struct {
+	// Locations of the matrix
+	Locations []Waypoint
+	// Distances and times between all locations (default true),
+	// otherwise from the first location to all others (one to many).
+	AllToAll bool
+	// Distances and times from all locations to the first location
+	// (if AllToAll is false)
+	ManyToOne bool
+	// type of units for calculating distance: m (Miles, default) or k (Km)
+	Unit Unit
+	// fastest(default), shortest, pedestrian, bicycle
+	RouteType RouteType
+}
+
+// NewRouteMatrix is a constructor to initialize an all to all RouteMatrix
+// with mapquest defaults.
+func NewRouteMatrix(locations []Waypoint) *RouteMatrix {
+	return &RouteMatrix{
+		Locations: locations,
+		AllToAll:  true,
+		Unit:      Miles,
+		RouteType: Fastest,
+	}
+}
+
+// RouteMatrix initializes an all to all RouteMatrix between the
+// waypoints of the directions with the same unit and route type.
+func (directions Directions) RouteMatrix() *RouteMatrix {
+	matrix := NewRouteMatrix(directions.Waypoints())
+	matrix.Unit = directions.Unit
+	matrix.RouteType = directions.RouteType
+	return matrix
+}
+
+// MatrixResults of a route matrix. Distance[i][j] and Time[i][j] are
+// from location i to location j. All to all results are n x n,
+// one to many results have a single row (i = 0) and many to one
+// results have a single column (j = 0).
+type MatrixResults struct {
+	// Distances in the unit of the route matrix
+	Distance [][]float64
+	// Times in seconds
+	Time [][]int
+	// Geocoded locations
+	Locations []Location
+	Info      Info
+}
+
+// Validate checks the route matrix options before any request is made.
+// All invalid options are reported together as ValidationErrors.
+func (matrix RouteMatrix) Validate() error {
+	v := &validator{}
+	v.check(len(matrix.Locations) > 1, "Locations needs at least 2 locations")
+	validateWaypoints(v, "Locations", matrix.Locations)
+	v.check(!matrix.AllToAll || !matrix.ManyToOne, "AllToAll and ManyToOne are exclusive")
+	matrix.Unit.validate(v)
+	v.oneOf("RouteType", string(matrix.RouteType),
+		string(Fastest), string(Shortest), string(Pedestrian), string(Bicycle))
+	return v.err()
+}
+
+// matrixOptions is the json options object of a route matrix request
+type matrixOptions struct {
+	AllToAll  bool      `json:"allToAll"`
+	ManyToOne bool      `json:"manyToOne"`
+	Unit      Unit      `json:"unit"`
+	RouteType RouteType `json:"routeType"`
+}
+
+// matrixBody will be marshalled as json to send in body with http post
+type matrixBody struct {
+	Locations []interface{} `json:"locations"`
+	Options   matrixOptions `json:"options"`
+}
+
+// matrixRows decodes a matrix (all to all) as well as a single row
+// (one to many, many to one) of a route matrix response.
+type matrixRows [][]float64
+
+func (rows *matrixRows) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(bytes.TrimSpace(bytes.TrimPrefix(data, []byte("["))), []byte("[")) {
+		return json.Unmarshal(data, (*[][]float64)(rows))
+	}
+	var row []float64
+	if err := json.Unmarshal(data, &row); err != nil {
+		return err
+	}
+	*rows = matrixRows{row}
+	return nil
+}
+
+// matrixResponse is the json response of a route matrix request
+type matrixResponse struct {
+	Distance  matrixRows `json:"distance"`
+	Time      matrixRows `json:"time"`
+	Locations []Location `json:"locations"`
+	Info      Info       `json:"info"`
+}
+
+// postRouteMatrix sends a single route matrix request
+var postRouteMatrix = func(body matrixBody) (*matrixResponse, error) {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.Post(directionsBaseURL+"routematrix?key="+apiKey, "application/json", bytes.NewBuffer(b))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	response := &matrixResponse{}
+	if err = decoder(resp).Decode(response); err != nil {
+		return nil, err
+	}
+	if response.Info.Statuscode != 0 {
+		return nil, response.Info
+	}
+	return response, nil
+}
+
+// request sends a route matrix request for the locations with the given indexes
+func (matrix RouteMatrix) request(indexes []int, allToAll, manyToOne bool) (*matrixResponse, error) {
+	body := matrixBody{
+		Options: matrixOptions{
+			AllToAll:  allToAll,
+			ManyToOne: manyToOne,
+			Unit:      matrix.Unit,
+			RouteType: matrix.RouteType,
+		},
+	}
+	for _, index := range indexes {
+		body.Locations = append(body.Locations, matrix.Locations[index].body())
+	}
+	return postRouteMatrix(body)
+}
+
+// Get the route matrix results. Large matrices are split into multiple
+// requests: all to all matrices of more than 25 locations are calculated
+// row by row, one to many (many to one) requests hold at most 100 locations.
+func (matrix RouteMatrix) Get() (*MatrixResults, error) {
+	if err := matrix.Validate(); err != nil {
+		return nil, err
+	}
+	n := len(matrix.Locations)
+	results := &MatrixResults{Locations: make([]Location, n)}
+	if matrix.AllToAll && n <= matrixAllToAllLimit {
+		indexes := make([]int, n)
+		for i := range indexes {
+			indexes[i] = i
+		}
+		response, err := matrix.request(indexes, true, false)
+		if err != nil {
+			return nil, err
+		}
+		results.Distance = response.Distance
+		results.Time = roundRows(response.Time)
+		copy(results.Locations, response.Locations)
+		results.Info = response.Info
+		return results, nil
+	}
+	rows, columns := 1, n
+	switch {
+	case matrix.AllToAll:
+		rows = n
+	case matrix.ManyToOne:
+		rows, columns = n, 1
+	}
+	results.Distance = make([][]float64, rows)
+	results.Time = make([][]int, rows)
+	for i := range results.Distance {
+		results.Distance[i] = make([]float64, columns)
+		results.Time[i] = make([]int, columns)
+	}
+	origins := []int{0}
+	if matrix.AllToAll {
+		origins = make([]int, n)
+		for i := range origins {
+			origins[i] = i
+		}
+	}
+	for _, origin := range origins {
+		// the other locations in chunks, each preceded by the origin
+		others := make([]int, 0, n-1)
+		for i := 0; i < n; i++ {
+			if i != origin {
+				others = append(others, i)
+			}
+		}
+		for start := 0; start < len(others); start += matrixOneToManyLimit - 1 {
+			end := start + matrixOneToManyLimit - 1
+			if end > len(others) {
+				end = len(others)
+			}
+			indexes := append([]int{origin}, others[start:end]...)
+			response, err := matrix.request(indexes, false, matrix.ManyToOne)
+			if err != nil {
+				return nil, err
+			}
+			results.Info = response.Info
+			for k, index := range indexes {
+				if k < len(response.Locations) {
+					results.Locations[index] = response.Locations[k]
+				}
+				if len(response.Distance) == 0 || k >= len(response.Distance[0]) || len(response.Time) == 0 || k >= len(response.Time[0]) {
+					continue
+				}
+				distance, time := response.Distance[0][k], int(response.Time[0][k]+0.5)
+				if matrix.ManyToOne {
+					results.Distance[index][0], results.Time[index][0] = distance, time
+				} else {
+					results.Distance[origin][index], results.Time[origin][index] = distance, time
+				}
+			}
+		}
+	}
+	return results, nil
+}
+
+// roundRows converts matrix rows of seconds into ints
+func roundRows(rows [][]float64) [][]int {
+	ints := make([][]int, len(rows))
+	for i, row := range rows {
+		ints[i] = make([]int, len(row))
+		for j, value := range row {
+			ints[i][j] = int(value + 0.5)
+		}
+	}
+	return ints
+}
@@ -0,0 +1,118 @@
+package geocoder
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"testing"
+)
+
+// fakeRouteMatrix answers route matrix requests for numbered addresses
+// with distance |i - j| and time 60 * |i - j|.
+func fakeRouteMatrix(requests *int) func(body matrixBody) (*matrixResponse, error) {
+	return func(body matrixBody) (*matrixResponse, error) {
+		*requests++
+		numbers := make([]float64, len(body.Locations))
+		for i, location := range body.Locations {
+			numbers[i], _ = strconv.ParseFloat(location.(string), 64)
+		}
+		row := func(from float64) (distances, times []float64) {
+			for _, to := range numbers {
+				distances = append(distances, math.Abs(to-from))
+				times = append(times, 60*math.Abs(to-from))
+			}
+			return
+		}
+		response := &matrixResponse{Locations: make([]Location, len(numbers))}
+		if body.Options.AllToAll {
+			for _, from := range numbers {
+				distances, times := row(from)
+				response.Distance = append(response.Distance, distances)
+				response.Time = append(response.Time, times)
+			}
+		} else {
+			distances, times := row(numbers[0])
+			response.Distance, response.Time = matrixRows{distances}, matrixRows{times}
+		}
+		return response, nil
+	}
+}
+
+func numberedWaypoints(n int) []Waypoint {
+	addresses := make([]string, n)
+	for i := range addresses {
+		addresses[i] = strconv.Itoa(i)
+	}
+	return AddressWaypoints(addresses)
+}
+
+func TestMatrixRows(t *testing.T) {
+	var response matrixResponse
+	err := json.Unmarshal([]byte(`{"distance":[0,1.5,2],"time":[[0,60],[60,0]]}`), &response)
+	if unexpected(err, t) {
+		return
+	}
+	if len(response.Distance) != 1 || len(response.Distance[0]) != 3 || response.Distance[0][1] != 1.5 {
+		t.Errorf("Distance: Expected a single row ~ Received %v", response.Distance)
+	}
+	if len(response.Time) != 2 || response.Time[1][0] != 60 {
+		t.Errorf("Time: Expected 2 rows ~ Received %v", response.Time)
+	}
+}
+
+func TestRouteMatrixChunks(t *testing.T) {
+	saved := postRouteMatrix
+	defer func() { postRouteMatrix = saved }()
+	requests := 0
+	postRouteMatrix = fakeRouteMatrix(&requests)
+
+	for _, test := range []struct {
+		n                   int
+		allToAll, manyToOne bool
+		rows, columns       int
+		requests            int
+	}{
+		{10, true, false, 10, 10, 1},
+		{30, true, false, 30, 30, 30},
+		{250, false, false, 1, 250, 3},
+		{150, false, true, 150, 1, 2},
+	} {
+		requests = 0
+		matrix := NewRouteMatrix(numberedWaypoints(test.n))
+		matrix.AllToAll, matrix.ManyToOne = test.allToAll, test.manyToOne
+		results, err := matrix.Get()
+		if unexpected(err, t) {
+			continue
+		}
+		if requests != test.requests {
+			t.Errorf("n=%d: Expected %d requests ~ Received %d", test.n, test.requests, requests)
+		}
+		if len(results.Distance) != test.rows || len(results.Distance[0]) != test.columns {
+			t.Errorf("n=%d: Expected %dx%d ~ Received %dx%d", test.n, test.rows, test.columns, len(results.Distance), len(results.Distance[0]))
+			continue
+		}
+		for i, row := range results.Distance {
+			for j, distance := range row {
+				from, to := i, j
+				switch {
+				case test.rows == 1:
+					from = 0
+				case test.columns == 1:
+					to = 0
+				}
+				if expected := math.Abs(float64(to - from)); distance != expected || results.Time[i][j] != int(60*expected) {
+					t.Errorf("n=%d [%d][%d]: Expected %g, %d ~ Received %g, %d", test.n, i, j, expected, int(60*expected), distance, results.Time[i][j])
+				}
+			}
+		}
+	}
+}
+
+func TestRouteMatrixValidate(t *testing.T) {
+	matrix := NewRouteMatrix(numberedWaypoints(1))
+	matrix.ManyToOne = true
+	matrix.RouteType = Multimodal
+	if errs, ok := matrix.Validate().(ValidationErrors); !ok || len(errs) != 3 {
+		t.Errorf("Expected 3 validation errors ~ Received %v", matrix.Validate())
+	}
+}
@@ -0,0 +1,429 @@
+/* Renders turn by turn narratives locally from the structured maneuvers.
+
+Mapquest only returns narratives for its supported locales; the renderer
+builds them with a text/template per maneuver kind and language.
+English (en), Dutch (nl), Polish (pl) and Portuguese (pt) are built in,
+other languages can be added with RegisterNarrativeLanguage.
+
+Example:
+
+renderer, err := NewNarrativeRenderer("nl", directions.Unit)
+instructions, err := renderer.Leg(results.Route.Legs[0])
+
+*/
+
+package geocoder
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// Maneuver kinds for which a language has a template
+const (
+	NarrativeStart    = "start"
+	NarrativeContinue = "continue"
+	NarrativeTurn     = "turn"
+	NarrativeUTurn    = "uturn"
+	NarrativeMerge    = "merge"
+	NarrativeRamp     = "ramp"
+	NarrativeExit     = "exit"
+	NarrativeFork     = "fork"
+	NarrativeArrive   = "arrive"
+)
+
+var narrativeKinds = []string{
+	NarrativeStart, NarrativeContinue, NarrativeTurn, NarrativeUTurn, NarrativeMerge,
+	NarrativeRamp, NarrativeExit, NarrativeFork, NarrativeArrive,
+}
+
+// NarrativeLanguage holds everything needed to render narratives in a language.
+type NarrativeLanguage struct {
+	// A text/template per maneuver kind (NarrativeStart, ...). The templates
+	// are executed with NarrativeData.
+	Templates map[string]string
+	// Turn words of turns (TurnSlightRight ... TurnSlightLeft), eg "slight right"
+	Turns map[TurnType]string
+	// Side words of merges, ramps, exits and forks: "right", "left" and "straight"
+	Sides map[string]string
+	// Compass direction words
+	Compass map[Compass]string
+	// Names of "mile", "kilometer", "foot" and "meter" per plural form
+	Units map[string][]string
+	// Plural returns the plural form (index in Units) of a number
+	Plural func(n float64) int
+	// Decimal separator
+	Decimal string
+}
+
+// NarrativeData is passed to the narrative templates
+type NarrativeData struct {
+	// Localized turn or side word
+	Turn string
+	// Localized compass direction (empty for CompassNone)
+	Direction string
+	// First street name (or the sign text if there is no street)
+	Street string
+	// Sign text, eg "A12" or "Utrecht"
+	Sign string
+	// Localized distance including its unit, eg "2,5 kilometer"
+	Distance string
+	// The maneuver itself
+	Maneuver Maneuver
+}
+
+// parsed narrative languages, guarded by narrativeLanguagesMutex
+var (
+	narrativeLanguages      = map[string]*narrativeLanguage{}
+	narrativeLanguagesMutex sync.RWMutex
+)
+
+type narrativeLanguage struct {
+	NarrativeLanguage
+	templates *template.Template
+}
+
+// RegisterNarrativeLanguage adds (or replaces) a language for narrative
+// rendering. All maneuver kinds need a template.
+func RegisterNarrativeLanguage(code string, language NarrativeLanguage) error {
+	if language.Plural == nil {
+		return fmt.Errorf("Narrative language %s: Plural is missing", code)
+	}
+	root := template.New(code)
+	for _, kind := range narrativeKinds {
+		text, ok := language.Templates[kind]
+		if !ok {
+			return fmt.Errorf("Narrative language %s: template %s is missing", code, kind)
+		}
+		if _, err := root.New(kind).Parse(text); err != nil {
+			return fmt.Errorf("Narrative language %s: %v", code, err)
+		}
+	}
+	for _, name := range []string{"mile", "kilometer", "foot", "meter"} {
+		if len(language.Units[name]) == 0 {
+			return fmt.Errorf("Narrative language %s: unit %s is missing", code, name)
+		}
+	}
+	narrativeLanguagesMutex.Lock()
+	defer narrativeLanguagesMutex.Unlock()
+	narrativeLanguages[code] = &narrativeLanguage{NarrativeLanguage: language, templates: root}
+	return nil
+}
+
+// NarrativeRenderer renders maneuver instructions in a language
+type NarrativeRenderer struct {
+	language *narrativeLanguage
+	// Unit of the maneuver distances (the unit of the directions)
+	Unit Unit
+}
+
+// NewNarrativeRenderer is a constructor to initialize a NarrativeRenderer
+// for a registered language (en, nl, pl, pt) and the unit of the route.
+func NewNarrativeRenderer(code string, unit Unit) (*NarrativeRenderer, error) {
+	narrativeLanguagesMutex.RLock()
+	language, ok := narrativeLanguages[code]
+	narrativeLanguagesMutex.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("Unknown narrative language %s", code)
+	}
+	return &NarrativeRenderer{language: language, Unit: unit}, nil
+}
+
+// narrativeKind returns the template kind and side of a turn type
+func narrativeKind(turnType TurnType) (kind, side string) {
+	switch turnType {
+	case TurnNone:
+		return NarrativeArrive, ""
+	case TurnStraight:
+		return NarrativeContinue, "straight"
+	case TurnReverse, TurnRightUTurn, TurnLeftUTurn:
+		return NarrativeUTurn, ""
+	case TurnRightMerge:
+		return NarrativeMerge, "right"
+	case TurnLeftMerge:
+		return NarrativeMerge, "left"
+	case TurnRightOnRamp:
+		return NarrativeRamp, "right"
+	case TurnLeftOnRamp:
+		return NarrativeRamp, "left"
+	case TurnRightOffRamp:
+		return NarrativeExit, "right"
+	case TurnLeftOffRamp:
+		return NarrativeExit, "left"
+	case TurnRightFork:
+		return NarrativeFork, "right"
+	case TurnLeftFork:
+		return NarrativeFork, "left"
+	case TurnStraightFork:
+		return NarrativeFork, "straight"
+	}
+	return NarrativeTurn, ""
+}
+
+// render executes the template of kind for a maneuver
+func (renderer *NarrativeRenderer) render(kind string, maneuver Maneuver) (string, error) {
+	language := renderer.language
+	_, side := narrativeKind(maneuver.TurnType)
+	data := NarrativeData{
+		Turn:      language.Turns[maneuver.TurnType],
+		Direction: language.Compass[maneuver.Direction],
+		Distance:  renderer.FormatDistance(maneuver.Distance),
+		Maneuver:  maneuver,
+	}
+	if side != "" {
+		data.Turn = language.Sides[side]
+	}
+	if len(maneuver.Signs) > 0 {
+		data.Sign = strings.TrimSpace(maneuver.Signs[0].Text + " " + maneuver.Signs[0].ExtraText)
+	}
+	data.Street = data.Sign
+	if len(maneuver.Streets) > 0 {
+		data.Street = maneuver.Streets[0]
+	}
+	var narrative bytes.Buffer
+	if err := language.templates.ExecuteTemplate(&narrative, kind, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(narrative.String()), nil
+}
+
+// Maneuver renders the instruction of a single maneuver based on its turn type.
+func (renderer *NarrativeRenderer) Maneuver(maneuver Maneuver) (string, error) {
+	kind, _ := narrativeKind(maneuver.TurnType)
+	return renderer.render(kind, maneuver)
+}
+
+// Leg renders the instructions of all maneuvers of a leg.
+// The first maneuver is rendered as start, the last one as arrival.
+func (renderer *NarrativeRenderer) Leg(leg Leg) ([]string, error) {
+	narratives := make([]string, len(leg.Maneuvers))
+	for i, maneuver := range leg.Maneuvers {
+		kind, _ := narrativeKind(maneuver.TurnType)
+		switch {
+		case i == 0:
+			kind = NarrativeStart
+		case i == len(leg.Maneuvers)-1 && maneuver.Distance == 0:
+			kind = NarrativeArrive
+		}
+		narrative, err := renderer.render(kind, maneuver)
+		if err != nil {
+			return nil, err
+		}
+		narratives[i] = narrative
+	}
+	return narratives, nil
+}
+
+// Route renders the instructions of all legs of a route.
+func (renderer *NarrativeRenderer) Route(route Route) ([][]string, error) {
+	narratives := make([][]string, len(route.Legs))
+	for i, leg := range route.Legs {
+		legNarratives, err := renderer.Leg(leg)
+		if err != nil {
+			return nil, err
+		}
+		narratives[i] = legNarratives
+	}
+	return narratives, nil
+}
+
+// FormatDistance formats a distance in the unit of the renderer with its
+// localized (pluralized) unit name. Short distances are given in
+// meters (km) or feet (miles), the unit is picked after rounding.
+func (renderer *NarrativeRenderer) FormatDistance(distance float64) string {
+	language := renderer.language
+	var value float64
+	var name string
+	decimals := 0
+	// meters rounded to 10, feet rounded to 50
+	meters := math.Round(distance*100) * 10
+	feet := math.Round(distance*5280/50) * 50
+	switch {
+	case renderer.Unit == Kilometers && meters < 1000:
+		value, name = math.Max(10, meters), "meter"
+	case renderer.Unit == Kilometers:
+		value, name, decimals = math.Round(distance*10)/10, "kilometer", 1
+	case feet < 528:
+		value, name = math.Max(50, feet), "foot"
+	default:
+		value, name, decimals = math.Round(distance*10)/10, "mile", 1
+	}
+	if value == math.Trunc(value) {
+		decimals = 0
+	}
+	number := strconv.FormatFloat(value, 'f', decimals, 64)
+	if language.Decimal != "" {
+		number = strings.Replace(number, ".", language.Decimal, 1)
+	}
+	forms := language.Units[name]
+	form := language.Plural(value)
+	if form >= len(forms) {
+		form = len(forms) - 1
+	}
+	return number + " " + forms[form]
+}
+
+// plural rules (see the CLDR plural rules)
+
+// pluralEnglish: 1 mile, 2 miles, 1.5 miles
+func pluralEnglish(n float64) int {
+	if n == 1 {
+		return 0
+	}
+	return 1
+}
+
+// pluralPortuguese: 0 and 1 are singular (Brazilian Portuguese)
+func pluralPortuguese(n float64) int {
+	if n == math.Trunc(n) && n <= 1 {
+		return 0
+	}
+	return 1
+}
+
+// pluralPolish: 1 kilometr, 2-4 kilometry, 5 kilometrów, 1,5 kilometra
+func pluralPolish(n float64) int {
+	if n != math.Trunc(n) {
+		return 3
+	}
+	i := int64(n)
+	switch {
+	case i == 1:
+		return 0
+	case i%10 >= 2 && i%10 <= 4 && (i%100 < 12 || i%100 > 14):
+		return 1
+	}
+	return 2
+}
+
+func init() {
+	languages := map[string]NarrativeLanguage{
+		"en": {
+			Templates: map[string]string{
+				NarrativeStart:    `Start out{{if .Direction}} going {{.Direction}}{{end}}{{if .Street}} on {{.Street}}{{end}} for {{.Distance}}.`,
+				NarrativeContinue: `Continue{{if .Street}} on {{.Street}}{{end}} for {{.Distance}}.`,
+				NarrativeTurn:     `Turn {{.Turn}}{{if .Street}} onto {{.Street}}{{end}}.`,
+				NarrativeUTurn:    `Make a U-turn{{if .Street}} onto {{.Street}}{{end}}.`,
+				NarrativeMerge:    `Merge {{.Turn}}{{if .Street}} onto {{.Street}}{{end}}.`,
+				NarrativeRamp:     `Take the ramp on the {{.Turn}}{{if .Street}} onto {{.Street}}{{end}}.`,
+				NarrativeExit:     `Take the exit on the {{.Turn}}{{if .Sign}} toward {{.Sign}}{{end}}.`,
+				NarrativeFork:     `Keep {{.Turn}} at the fork{{if .Street}} onto {{.Street}}{{end}}.`,
+				NarrativeArrive:   `Arrive at your destination.`,
+			},
+			Turns: map[TurnType]string{
+				TurnSlightRight: "slight right", TurnRight: "right", TurnSharpRight: "sharp right",
+				TurnSharpLeft: "sharp left", TurnLeft: "left", TurnSlightLeft: "slight left",
+			},
+			Sides: map[string]string{"right": "right", "left": "left", "straight": "straight"},
+			Compass: map[Compass]string{
+				CompassNorth: "north", CompassNorthwest: "northwest", CompassNortheast: "northeast",
+				CompassSouth: "south", CompassSoutheast: "southeast", CompassSouthwest: "southwest",
+				CompassWest: "west", CompassEast: "east",
+			},
+			Units: map[string][]string{
+				"mile": {"mile", "miles"}, "kilometer": {"kilometer", "kilometers"},
+				"foot": {"foot", "feet"}, "meter": {"meter", "meters"},
+			},
+			Plural: pluralEnglish,
+		},
+		"nl": {
+			Templates: map[string]string{
+				NarrativeStart:    `Vertrek{{if .Direction}} in {{.Direction}} richting{{end}}{{if .Street}} over {{.Street}}{{end}} en rijd {{.Distance}}.`,
+				NarrativeContinue: `Rijd {{.Distance}} rechtdoor{{if .Street}} over {{.Street}}{{end}}.`,
+				NarrativeTurn:     `Sla {{.Turn}} af{{if .Street}} naar {{.Street}}{{end}}.`,
+				NarrativeUTurn:    `Keer om{{if .Street}} naar {{.Street}}{{end}}.`,
+				NarrativeMerge:    `Voeg {{.Turn}} in{{if .Street}} op {{.Street}}{{end}}.`,
+				NarrativeRamp:     `Neem de oprit {{.Turn}}{{if .Street}} naar {{.Street}}{{end}}.`,
+				NarrativeExit:     `Neem de afrit {{.Turn}}{{if .Sign}} richting {{.Sign}}{{end}}.`,
+				NarrativeFork:     `Houd {{.Turn}} aan bij de splitsing{{if .Street}} naar {{.Street}}{{end}}.`,
+				NarrativeArrive:   `U bent op uw bestemming aangekomen.`,
+			},
+			Turns: map[TurnType]string{
+				TurnSlightRight: "flauw rechts", TurnRight: "rechts", TurnSharpRight: "scherp rechts",
+				TurnSharpLeft: "scherp links", TurnLeft: "links", TurnSlightLeft: "flauw links",
+			},
+			Sides: map[string]string{"right": "rechts", "left": "links", "straight": "rechtdoor"},
+			Compass: map[Compass]string{
+				CompassNorth: "noordelijke", CompassNorthwest: "noordwestelijke", CompassNortheast: "noordoostelijke",
+				CompassSouth: "zuidelijke", CompassSoutheast: "zuidoostelijke", CompassSouthwest: "zuidwestelijke",
+				CompassWest: "westelijke", CompassEast: "oostelijke",
+			},
+			// Dutch keeps the unit singular after a number
+			Units: map[string][]string{
+				"mile": {"mijl"}, "kilometer": {"kilometer"}, "foot": {"voet"}, "meter": {"meter"},
+			},
+			Plural:  pluralEnglish,
+			Decimal: ",",
+		},
+		"pl": {
+			Templates: map[string]string{
+				NarrativeStart:    `Jedź{{if .Direction}} na {{.Direction}}{{end}}{{if .Street}} ulicą {{.Street}}{{end}} przez {{.Distance}}.`,
+				NarrativeContinue: `Jedź dalej{{if .Street}} ulicą {{.Street}}{{end}} przez {{.Distance}}.`,
+				NarrativeTurn:     `Skręć {{.Turn}}{{if .Street}} w {{.Street}}{{end}}.`,
+				NarrativeUTurn:    `Zawróć{{if .Street}} na {{.Street}}{{end}}.`,
+				NarrativeMerge:    `Włącz się do ruchu {{.Turn}}{{if .Street}} na {{.Street}}{{end}}.`,
+				NarrativeRamp:     `Skręć {{.Turn}} na wjazd{{if .Street}} na {{.Street}}{{end}}.`,
+				NarrativeExit:     `Zjedź {{.Turn}}{{if .Sign}} w kierunku {{.Sign}}{{end}}.`,
+				NarrativeFork:     `Na rozwidleniu jedź {{.Turn}}{{if .Street}} na {{.Street}}{{end}}.`,
+				NarrativeArrive:   `Dotarłeś do celu.`,
+			},
+			Turns: map[TurnType]string{
+				TurnSlightRight: "lekko w prawo", TurnRight: "w prawo", TurnSharpRight: "ostro w prawo",
+				TurnSharpLeft: "ostro w lewo", TurnLeft: "w lewo", TurnSlightLeft: "lekko w lewo",
+			},
+			Sides: map[string]string{"right": "w prawo", "left": "w lewo", "straight": "prosto"},
+			Compass: map[Compass]string{
+				CompassNorth: "północ", CompassNorthwest: "północny zachód", CompassNortheast: "północny wschód",
+				CompassSouth: "południe", CompassSoutheast: "południowy wschód", CompassSouthwest: "południowy zachód",
+				CompassWest: "zachód", CompassEast: "wschód",
+			},
+			Units: map[string][]string{
+				"mile":      {"mila", "mile", "mil", "mili"},
+				"kilometer": {"kilometr", "kilometry", "kilometrów", "kilometra"},
+				"foot":      {"stopa", "stopy", "stóp", "stopy"},
+				"meter":     {"metr", "metry", "metrów", "metra"},
+			},
+			Plural:  pluralPolish,
+			Decimal: ",",
+		},
+		"pt": {
+			Templates: map[string]string{
+				NarrativeStart:    `Siga{{if .Direction}} para o {{.Direction}}{{end}}{{if .Street}} na {{.Street}}{{end}} por {{.Distance}}.`,
+				NarrativeContinue: `Continue{{if .Street}} na {{.Street}}{{end}} por {{.Distance}}.`,
+				NarrativeTurn:     `Vire {{.Turn}}{{if .Street}} na {{.Street}}{{end}}.`,
+				NarrativeUTurn:    `Faça o retorno{{if .Street}} na {{.Street}}{{end}}.`,
+				NarrativeMerge:    `Entre {{.Turn}}{{if .Street}} na {{.Street}}{{end}}.`,
+				NarrativeRamp:     `Pegue a rampa {{.Turn}}{{if .Street}} para {{.Street}}{{end}}.`,
+				NarrativeExit:     `Pegue a saída {{.Turn}}{{if .Sign}} em direção a {{.Sign}}{{end}}.`,
+				NarrativeFork:     `Mantenha-se {{.Turn}} na bifurcação{{if .Street}} para {{.Street}}{{end}}.`,
+				NarrativeArrive:   `Você chegou ao seu destino.`,
+			},
+			Turns: map[TurnType]string{
+				TurnSlightRight: "levemente à direita", TurnRight: "à direita", TurnSharpRight: "acentuadamente à direita",
+				TurnSharpLeft: "acentuadamente à esquerda", TurnLeft: "à esquerda", TurnSlightLeft: "levemente à esquerda",
+			},
+			Sides: map[string]string{"right": "à direita", "left": "à esquerda", "straight": "em frente"},
+			Compass: map[Compass]string{
+				CompassNorth: "norte", CompassNorthwest: "noroeste", CompassNortheast: "nordeste",
+				CompassSouth: "sul", CompassSoutheast: "sudeste", CompassSouthwest: "sudoeste",
+				CompassWest: "oeste", CompassEast: "leste",
+			},
+			Units: map[string][]string{
+				"mile": {"milha", "milhas"}, "kilometer": {"quilômetro", "quilômetros"},
+				"foot": {"pé", "pés"}, "meter": {"metro", "metros"},
+			},
+			Plural:  pluralPortuguese,
+			Decimal: ",",
+		},
+	}
+	for code, language := range languages {
+		if err := RegisterNarrativeLanguage(code, language); err != nil {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package geocoder
+
+import "testing"
+
+func TestNarrativeLeg(t *testing.T) {
+	leg := Leg{Maneuvers: []Maneuver{
+		{TurnType: TurnStraight, Direction: CompassNorth, Streets: []string{"Damrak"}, Distance: 2.5},
+		{TurnType: TurnLeft, Streets: []string{"Rokin"}, Distance: 0.3},
+		{TurnType: TurnRightOffRamp, Signs: []Sign{{Text: "A10"}}, Distance: 1},
+		{TurnType: TurnNone},
+	}}
+	expected := map[string][]string{
+		"en": {
+			"Start out going north on Damrak for 2.5 kilometers.",
+			"Turn left onto Rokin.",
+			"Take the exit on the right toward A10.",
+			"Arrive at your destination.",
+		},
+		"nl": {
+			"Vertrek in noordelijke richting over Damrak en rijd 2,5 kilometer.",
+			"Sla links af naar Rokin.",
+			"Neem de afrit rechts richting A10.",
+			"U bent op uw bestemming aangekomen.",
+		},
+	}
+	for code, narratives := range expected {
+		renderer, err := NewNarrativeRenderer(code, Kilometers)
+		if unexpected(err, t) {
+			return
+		}
+		received, err := renderer.Leg(leg)
+		if unexpected(err, t) {
+			return
+		}
+		for i := range narratives {
+			if received[i] != narratives[i] {
+				t.Errorf("%s %d: Expected %s ~ Received %s", code, i, narratives[i], received[i])
+			}
+		}
+	}
+	if _, err := NewNarrativeRenderer("xx", Miles); err == nil {
+		t.Errorf("Expected an error for an unknown language")
+	}
+}
+
+func TestNarrativeDistance(t *testing.T) {
+	tests := []struct {
+		code     string
+		unit     Unit
+		distance float64
+		expected string
+	}{
+		{"en", Miles, 1, "1 mile"},
+		{"en", Miles, 0.05, "250 feet"},
+		{"en", Kilometers, 0.34, "340 meters"},
+		{"en", Kilometers, 0.999, "1 kilometer"},
+		{"en", Kilometers, 0.994, "990 meters"},
+		{"en", Miles, 0.0999, "0.1 miles"},
+		{"pl", Kilometers, 1, "1 kilometr"},
+		{"pl", Kilometers, 3, "3 kilometry"},
+		{"pl", Kilometers, 12, "12 kilometrów"},
+		{"pl", Kilometers, 22, "22 kilometry"},
+		{"pl", Kilometers, 1.5, "1,5 kilometra"},
+		{"pt", Miles, 1, "1 milha"},
+		{"pt", Kilometers, 2.25, "2,3 quilômetros"},
+		{"nl", Miles, 4, "4 mijl"},
+	}
+	for _, test := range tests {
+		renderer, err := NewNarrativeRenderer(test.code, test.unit)
+		if unexpected(err, t) {
+			return
+		}
+		if received := renderer.FormatDistance(test.distance); received != test.expected {
+			t.Errorf("%s: Expected %s ~ Received %s", test.code, test.expected, received)
+		}
+	}
+}
+
+func TestNarrativeManeuver(t *testing.T) {
+	renderer, err := NewNarrativeRenderer("pt", Kilometers)
+	if unexpected(err, t) {
+		return
+	}
+	received, err := renderer.Maneuver(Maneuver{TurnType: TurnSlightRight, Streets: []string{"Avenida Paulista"}})
+	if unexpected(err, t) {
+		return
+	}
+	if received != "Vire levemente à direita na Avenida Paulista." {
+		t.Errorf("Expected Vire levemente à direita na Avenida Paulista. ~ Received %s", received)
+	}
+	// without a compass direction the direction phrase is left out
+	for code, expected := range map[string]string{
+		"en": "Start out on Damrak for 2.5 kilometers.",
+		"nl": "Vertrek over Damrak en rijd 2,5 kilometer.",
+	} {
+		renderer, err := NewNarrativeRenderer(code, Kilometers)
+		if unexpected(err, t) {
+			continue
+		}
+		narratives, err := renderer.Leg(Leg{Maneuvers: []Maneuver{{TurnType: TurnStraight, Direction: CompassNone, Streets: []string{"Damrak"}, Distance: 2.5}}})
+		if !unexpected(err, t) && narratives[0] != expected {
+			t.Errorf("%s: Expected %s ~ Received %s", code, expected, narratives[0])
+		}
+	}
+	if err := RegisterNarrativeLanguage("xx", NarrativeLanguage{Plural: pluralEnglish}); err == nil {
+		t.Errorf("Expected an error for missing templates")
+	}
+}
@@ -0,0 +1,30 @@
+package geocoder
+
+import "fmt"
+
+// OptimizedRoute gets the Direction Results (Route & Info) of the route
+// which visits all locations in the most efficient order. The first and
+// last location stay in place; Route.LocationSequence holds the new order
+// (see Route.Reorder).
+func (directions Directions) OptimizedRoute() (*DirectionsResults, error) {
+	return directions.route("optimizedroute")
+}
+
+// Reorder returns the waypoints (as passed to the directions, see
+// Directions.Waypoints) in the order of the route's LocationSequence.
+func (route Route) Reorder(waypoints []Waypoint) ([]Waypoint, error) {
+	sequence := route.LocationSequence
+	if len(sequence) != len(waypoints) {
+		return nil, fmt.Errorf("Location sequence has %d locations, expected %d", len(sequence), len(waypoints))
+	}
+	reordered := make([]Waypoint, len(waypoints))
+	seen := make([]bool, len(waypoints))
+	for i, index := range sequence {
+		if index < 0 || index >= len(waypoints) || seen[index] {
+			return nil, fmt.Errorf("Invalid location sequence: %v", sequence)
+		}
+		seen[index] = true
+		reordered[i] = waypoints[index]
+	}
+	return reordered, nil
+}
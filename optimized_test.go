@@ -0,0 +1,26 @@
+package geocoder
+
+import "testing"
+
+func TestReorder(t *testing.T) {
+	directions := NewDirections("Amsterdam,Netherlands", []string{"Brussels,Belgium", "Antwerp,Belgium", "Paris,France"})
+	route := Route{LocationSequence: []int{0, 2, 1, 3}}
+	waypoints, err := route.Reorder(directions.Waypoints())
+	if unexpected(err, t) {
+		return
+	}
+	expected := []string{"Amsterdam,Netherlands", "Antwerp,Belgium", "Brussels,Belgium", "Paris,France"}
+	for i, waypoint := range waypoints {
+		if waypoint.Address != expected[i] {
+			t.Errorf("Waypoint %d: Expected %s ~ Received %s", i, expected[i], waypoint.Address)
+		}
+	}
+	route.LocationSequence = []int{0, 2, 2, 3}
+	if _, err = route.Reorder(directions.Waypoints()); err == nil {
+		t.Errorf("Duplicate index: Expected error ~ Received nil")
+	}
+	route.LocationSequence = []int{0, 1}
+	if _, err = route.Reorder(directions.Waypoints()); err == nil {
+		t.Errorf("Short sequence: Expected error ~ Received nil")
+	}
+}
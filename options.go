@@ -0,0 +1,187 @@
+package geocoder
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Unit of distance
+type Unit string
+
+// Units
+const (
+	Miles      Unit = "m"
+	Kilometers Unit = "k"
+)
+
+// validate checks that the unit is Miles or Kilometers
+func (unit Unit) validate(v *validator) {
+	v.oneOf("Unit", string(unit), string(Miles), string(Kilometers))
+}
+
+// RouteType specifies the type of route wanted
+type RouteType string
+
+// Route types
+const (
+	Fastest    RouteType = "fastest"
+	Shortest   RouteType = "shortest"
+	Pedestrian RouteType = "pedestrian"
+	Multimodal RouteType = "multimodal"
+	Bicycle    RouteType = "bicycle"
+)
+
+// NarrativeType specifies the format of the narrative
+type NarrativeType string
+
+// Narrative types
+const (
+	NarrativeNone        NarrativeType = "none"
+	NarrativeText        NarrativeType = "text"
+	NarrativeHTML        NarrativeType = "html"
+	NarrativeMicroformat NarrativeType = "microformat"
+)
+
+// Locale specifies the language of the narrative
+type Locale string
+
+// Supported locales
+const (
+	LocaleEnUS Locale = "en_US"
+	LocaleEnGB Locale = "en_GB"
+	LocaleFrCA Locale = "fr_CA"
+	LocaleFrFR Locale = "fr_FR"
+	LocaleDeDE Locale = "de_DE"
+	LocaleEsES Locale = "es_ES"
+	LocaleEsMX Locale = "es_MX"
+	LocaleRuRU Locale = "ru_RU"
+)
+
+// Avoid is a road type which the route should avoid
+type Avoid string
+
+// Road types to avoid
+const (
+	AvoidLimitedAccess   Avoid = "Limited Access"
+	AvoidTollRoad        Avoid = "Toll Road"
+	AvoidFerry           Avoid = "Ferry"
+	AvoidUnpaved         Avoid = "Unpaved"
+	AvoidSeasonalClosure Avoid = "Seasonal Closure"
+	AvoidCountryCrossing Avoid = "Country Crossing"
+)
+
+// RoadGradeStrategy specifies how hills are treated
+type RoadGradeStrategy string
+
+// Road grade strategies
+const (
+	RoadGradeDefault       RoadGradeStrategy = "DEFAULT_STRATEGY"
+	RoadGradeAvoidUpHill   RoadGradeStrategy = "AVOID_UP_HILL"
+	RoadGradeAvoidDownHill RoadGradeStrategy = "AVOID_DOWN_HILL"
+	RoadGradeAvoidAllHills RoadGradeStrategy = "AVOID_ALL_HILLS"
+	RoadGradeFavorUpHill   RoadGradeStrategy = "FAVOR_UP_HILL"
+	RoadGradeFavorDownHill RoadGradeStrategy = "FAVOR_DOWN_HILL"
+	RoadGradeFavorAllHills RoadGradeStrategy = "FAVOR_ALL_HILLS"
+)
+
+// DrivingStyle is used for the fuel usage calculation
+type DrivingStyle string
+
+// Driving styles
+const (
+	DrivingCautious   DrivingStyle = "cautious"
+	DrivingNormal     DrivingStyle = "normal"
+	DrivingAggressive DrivingStyle = "aggressive"
+)
+
+// ShapeFormat specifies how the shape points are returned
+type ShapeFormat string
+
+// Shape formats
+const (
+	ShapeRaw  ShapeFormat = "raw"
+	ShapeCmp  ShapeFormat = "cmp"
+	ShapeCmp6 ShapeFormat = "cmp6"
+)
+
+// ValidationErrors aggregates all invalid options found by a Validate method
+type ValidationErrors []error
+
+func (errs ValidationErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return "Invalid options: " + strings.Join(messages, "; ")
+}
+
+// validator collects validation errors
+type validator struct {
+	errs ValidationErrors
+}
+
+// check adds an error when the condition does not hold
+func (v *validator) check(ok bool, format string, a ...interface{}) {
+	if !ok {
+		v.errs = append(v.errs, fmt.Errorf(format, a...))
+	}
+}
+
+// oneOf adds an error when value is not one of the allowed values
+func (v *validator) oneOf(name string, value string, allowed ...string) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	v.errs = append(v.errs, fmt.Errorf("%s %q is not one of %s", name, value, strings.Join(allowed, ", ")))
+}
+
+// err returns the aggregated errors or nil
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+// Validate checks the directions options before any request is made.
+// All invalid options are reported together as ValidationErrors.
+func (directions Directions) Validate() error {
+	v := &validator{}
+	directions.From.validate(v, "From")
+	v.check(len(directions.To) > 0, "To is empty")
+	validateWaypoints(v, "To", directions.To)
+	directions.Unit.validate(v)
+	v.oneOf("RouteType", string(directions.RouteType),
+		string(Fastest), string(Shortest), string(Pedestrian), string(Multimodal), string(Bicycle))
+	v.oneOf("NarrativeType", string(directions.NarrativeType),
+		string(NarrativeNone), string(NarrativeText), string(NarrativeHTML), string(NarrativeMicroformat))
+	v.oneOf("Locale", string(directions.Locale),
+		string(LocaleEnUS), string(LocaleEnGB), string(LocaleFrCA), string(LocaleFrFR),
+		string(LocaleDeDE), string(LocaleEsES), string(LocaleEsMX), string(LocaleRuRU))
+	for _, avoid := range directions.Avoids {
+		v.oneOf("Avoids", string(avoid),
+			string(AvoidLimitedAccess), string(AvoidTollRoad), string(AvoidFerry),
+			string(AvoidUnpaved), string(AvoidSeasonalClosure), string(AvoidCountryCrossing))
+	}
+	v.oneOf("RoadGradeStrategy", string(directions.RoadGradeStrategy),
+		string(RoadGradeDefault), string(RoadGradeAvoidUpHill), string(RoadGradeAvoidDownHill),
+		string(RoadGradeAvoidAllHills), string(RoadGradeFavorUpHill), string(RoadGradeFavorDownHill),
+		string(RoadGradeFavorAllHills))
+	v.oneOf("DrivingStyle", string(directions.DrivingStyle),
+		string(DrivingCautious), string(DrivingNormal), string(DrivingAggressive))
+	v.oneOf("ShapeFormat", string(directions.ShapeFormat),
+		string(ShapeRaw), string(ShapeCmp), string(ShapeCmp6))
+	v.check(directions.MaxLinkID >= 0, "MaxLinkID %d is negative", directions.MaxLinkID)
+	v.check(directions.CyclingRoadFactor >= 0.1 && directions.CyclingRoadFactor <= 100,
+		"CyclingRoadFactor %g is not within [0.1..100]", directions.CyclingRoadFactor)
+	v.check(directions.HighwayEfficiency >= 0 && directions.HighwayEfficiency <= 235,
+		"HighwayEfficiency %g is not within [0..235]", directions.HighwayEfficiency)
+	v.check(directions.WalkingSpeed == -1 || directions.WalkingSpeed > 0,
+		"WalkingSpeed %g is not positive (or -1 for the default)", directions.WalkingSpeed)
+	directions.validateTime(v)
+	v.check(directions.MaxRoutes >= 0, "MaxRoutes %d is negative", directions.MaxRoutes)
+	v.check(directions.TimeOverage >= 0, "TimeOverage %g is negative", directions.TimeOverage)
+	return v.err()
+}
@@ -0,0 +1,41 @@
+package geocoder
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	directions := NewDirections("Amsterdam,Netherlands", []string{"Antwerp,Belgium"})
+	if err := directions.Validate(); err != nil {
+		t.Errorf("Defaults: Expected nil ~ Received %v", err)
+	}
+	directions.Unit = "km"
+	directions.RouteType = "fastets"
+	directions.Avoids = []Avoid{AvoidTollRoad, "Tolls"}
+	directions.CyclingRoadFactor = 0
+	directions.HighwayEfficiency = 300
+	err := directions.Validate()
+	errs, ok := err.(ValidationErrors)
+	if !ok {
+		t.Fatalf("Expected ValidationErrors ~ Received %v", err)
+	}
+	if len(errs) != 5 {
+		t.Errorf("Expected 5 errors ~ Received %d: %v", len(errs), errs)
+	}
+	for _, expected := range []string{`Unit "km"`, `RouteType "fastets"`, `Avoids "Tolls"`, "CyclingRoadFactor 0", "HighwayEfficiency 300"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected %s in %s", expected, err.Error())
+		}
+	}
+}
+
+func TestValidateBeforeRequest(t *testing.T) {
+	directions := NewDirections("Amsterdam,Netherlands", nil)
+	if _, err := directions.Get(); err == nil || !strings.HasPrefix(err.Error(), "Invalid options: To is empty") {
+		t.Errorf("Expected validation error ~ Received %v", err)
+	}
+	if _, err := directions.Distance("miles"); err == nil {
+		t.Errorf("Expected validation error ~ Received nil")
+	}
+}
@@ -0,0 +1,226 @@
+/* In-memory spatial index for nearest neighbor searches over points,
+eg the stores geocoded with BatchGeocode.
+
+The points are stored in a k-d tree of 3D unit vectors, so the searches use
+exact great circle distances, also near the poles and the antimeridian.
+NearestByRoute refines the nearest candidates with a route matrix.
+
+Example:
+
+stores, err := BatchGeocode(addresses)
+index := NewPointIndex(stores, Kilometers)
+nearest := index.Nearest(customer, 1)[0]
+store := addresses[nearest.Index]
+
+*/
+
+package geocoder
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+// Neighbor is a point of a PointIndex found by a search
+type Neighbor struct {
+	// Index of the point in the points of the index
+	Index  int
+	LatLng LatLng
+	// Distance in the unit of the index (great circle or by route)
+	Distance float64
+	// Time in seconds (by route only)
+	Time int
+}
+
+// pointNode is a point of the k-d tree
+type pointNode struct {
+	index  int
+	vector [3]float64
+}
+
+// PointIndex is a static k-d tree of points
+type PointIndex struct {
+	// Unit of the distances: m (Miles) or k (Km)
+	Unit   Unit
+	points []LatLng
+	// nodes in k-d tree order: the median of a range is its root
+	nodes []pointNode
+}
+
+// unitVector returns the point on the unit sphere
+func unitVector(point LatLng) [3]float64 {
+	lat, lng := radians(point.Lat), radians(point.Lng)
+	return [3]float64{math.Cos(lat) * math.Cos(lng), math.Cos(lat) * math.Sin(lng), math.Sin(lat)}
+}
+
+// chord returns the squared straight line distance between unit vectors
+func chord(a, b [3]float64) float64 {
+	dx, dy, dz := a[0]-b[0], a[1]-b[1], a[2]-b[2]
+	return dx*dx + dy*dy + dz*dz
+}
+
+// chordMeters converts a squared chord into a great circle distance in meters
+func chordMeters(squared float64) float64 {
+	return 2 * math.Asin(math.Min(1, math.Sqrt(squared)/2)) * earthRadius
+}
+
+// NewPointIndex is a constructor to initialize a PointIndex over the points
+// with distances in unit.
+func NewPointIndex(points []LatLng, unit Unit) *PointIndex {
+	index := &PointIndex{Unit: unit, points: points, nodes: make([]pointNode, len(points))}
+	for i, point := range points {
+		index.nodes[i] = pointNode{index: i, vector: unitVector(point)}
+	}
+	index.build(index.nodes, 0)
+	return index
+}
+
+// build orders nodes as a k-d tree splitting on axis at depth 0
+func (index *PointIndex) build(nodes []pointNode, axis int) {
+	if len(nodes) < 2 {
+		return
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].vector[axis] < nodes[j].vector[axis] })
+	median := len(nodes) / 2
+	index.build(nodes[:median], (axis+1)%3)
+	index.build(nodes[median+1:], (axis+1)%3)
+}
+
+// Len returns the number of points in the index
+func (index *PointIndex) Len() int {
+	return len(index.points)
+}
+
+// nodeDistance is a node at a squared chord from the target
+type nodeDistance struct {
+	node     pointNode
+	distance float64
+}
+
+// neighborHeap is a max heap of the nearest nodes found so far
+type neighborHeap []nodeDistance
+
+func (h neighborHeap) Len() int            { return len(h) }
+func (h neighborHeap) Less(i, j int) bool  { return h[i].distance > h[j].distance }
+func (h neighborHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *neighborHeap) Push(x interface{}) { *h = append(*h, x.(nodeDistance)) }
+func (h *neighborHeap) Pop() interface{} {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
+
+// searchNodes visits the nodes which may lie within the squared chord limit()
+// of target, calling visit for each of them.
+func searchNodes(nodes []pointNode, axis int, target [3]float64, limit func() float64, visit func(node pointNode, distance float64)) {
+	if len(nodes) == 0 {
+		return
+	}
+	median := len(nodes) / 2
+	node := nodes[median]
+	visit(node, chord(node.vector, target))
+	near, far := nodes[:median], nodes[median+1:]
+	difference := target[axis] - node.vector[axis]
+	if difference > 0 {
+		near, far = far, near
+	}
+	searchNodes(near, (axis+1)%3, target, limit, visit)
+	if difference*difference <= limit() {
+		searchNodes(far, (axis+1)%3, target, limit, visit)
+	}
+}
+
+// neighbor converts a node at a squared chord into a Neighbor
+func (index *PointIndex) neighbor(node pointNode, squared float64) Neighbor {
+	return Neighbor{Index: node.index, LatLng: index.points[node.index], Distance: chordMeters(squared) / index.Unit.meters()}
+}
+
+// Nearest returns the k points nearest to point, nearest first.
+func (index *PointIndex) Nearest(point LatLng, k int) []Neighbor {
+	if k <= 0 {
+		return nil
+	}
+	h := &neighborHeap{}
+	limit := func() float64 {
+		if h.Len() < k {
+			return math.Inf(1)
+		}
+		return (*h)[0].distance
+	}
+	searchNodes(index.nodes, 0, unitVector(point), limit, func(node pointNode, distance float64) {
+		if h.Len() < k {
+			heap.Push(h, nodeDistance{node, distance})
+		} else if distance < (*h)[0].distance {
+			(*h)[0].node, (*h)[0].distance = node, distance
+			heap.Fix(h, 0)
+		}
+	})
+	neighbors := make([]Neighbor, h.Len())
+	for i := len(neighbors) - 1; i >= 0; i-- {
+		item := heap.Pop(h).(nodeDistance)
+		neighbors[i] = index.neighbor(item.node, item.distance)
+	}
+	return neighbors
+}
+
+// Within returns the points within a radius (in the unit of the index)
+// of point, nearest first.
+func (index *PointIndex) Within(point LatLng, radius float64) []Neighbor {
+	angle := math.Min(math.Pi, radius*index.Unit.meters()/earthRadius)
+	limit := 4 * math.Pow(math.Sin(angle/2), 2)
+	var neighbors []Neighbor
+	searchNodes(index.nodes, 0, unitVector(point), func() float64 { return limit }, func(node pointNode, distance float64) {
+		if distance <= limit {
+			neighbors = append(neighbors, index.neighbor(node, distance))
+		}
+	})
+	sort.Slice(neighbors, func(i, j int) bool { return neighbors[i].Distance < neighbors[j].Distance })
+	return neighbors
+}
+
+// NearestByRoute returns the k points nearest to point by route distance.
+// The candidates points nearest by great circle distance are measured with
+// a single one to many route matrix of the route type. Unroutable candidates
+// (a route distance of 0 or less away from the point) are left out.
+func (index *PointIndex) NearestByRoute(point LatLng, k, candidates int, routeType RouteType) ([]Neighbor, error) {
+	if candidates < k {
+		candidates = k
+	}
+	if candidates > matrixOneToManyLimit-1 {
+		candidates = matrixOneToManyLimit - 1
+	}
+	neighbors := index.Nearest(point, candidates)
+	if len(neighbors) == 0 {
+		return nil, nil
+	}
+	waypoints := []Waypoint{NewLatLngWaypoint(point)}
+	for _, neighbor := range neighbors {
+		waypoints = append(waypoints, NewLatLngWaypoint(neighbor.LatLng))
+	}
+	matrix := NewRouteMatrix(waypoints)
+	matrix.AllToAll = false
+	matrix.Unit = index.Unit
+	matrix.RouteType = routeType
+	results, err := matrix.Get()
+	if err != nil {
+		return nil, err
+	}
+	routable := neighbors[:0]
+	for i, neighbor := range neighbors {
+		distance := results.Distance[0][i+1]
+		if distance <= 0 && neighbor.Distance > 0 {
+			continue
+		}
+		neighbor.Distance = distance
+		neighbor.Time = results.Time[0][i+1]
+		routable = append(routable, neighbor)
+	}
+	neighbors = routable
+	sort.SliceStable(neighbors, func(i, j int) bool { return neighbors[i].Distance < neighbors[j].Distance })
+	if len(neighbors) > k {
+		neighbors = neighbors[:k]
+	}
+	return neighbors, nil
+}
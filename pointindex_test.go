@@ -0,0 +1,84 @@
+package geocoder
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func randomPoints(n int) []LatLng {
+	random := rand.New(rand.NewSource(1))
+	points := make([]LatLng, n)
+	for i := range points {
+		points[i] = LatLng{Lat: random.Float64()*180 - 90, Lng: random.Float64()*360 - 180}
+	}
+	return points
+}
+
+func TestPointIndexNearest(t *testing.T) {
+	points := randomPoints(2000)
+	index := NewPointIndex(points, Kilometers)
+	for _, target := range []LatLng{{Lat: 51.2, Lng: 4.4}, {Lat: 0, Lng: 179.99}, {Lat: 89.9, Lng: 0}} {
+		expected := make([]int, len(points))
+		for i := range expected {
+			expected[i] = i
+		}
+		sort.Slice(expected, func(i, j int) bool {
+			return haversine(target, points[expected[i]]) < haversine(target, points[expected[j]])
+		})
+		neighbors := index.Nearest(target, 5)
+		for i, neighbor := range neighbors {
+			if neighbor.Index != expected[i] || math.Abs(neighbor.Distance-target.Distance(points[expected[i]], Kilometers)) > 1e-6 {
+				t.Errorf("%v %d: Expected %d ~ Received %+v", target, i, expected[i], neighbor)
+			}
+		}
+		radius := neighbors[4].Distance
+		if within := index.Within(target, radius); len(within) != 5 || within[4].Index != expected[4] {
+			t.Errorf("%v: Expected the 5 nearest ~ Received %v", target, within)
+		}
+	}
+	if neighbors := NewPointIndex(nil, Miles).Nearest(LatLng{}, 3); len(neighbors) != 0 {
+		t.Errorf("Expected no neighbors ~ Received %v", neighbors)
+	}
+}
+
+func TestPointIndexNearestByRoute(t *testing.T) {
+	saved := postRouteMatrix
+	defer func() { postRouteMatrix = saved }()
+	// the route to the nearest point is a long detour
+	postRouteMatrix = func(body matrixBody) (*matrixResponse, error) {
+		row := []float64{0, 50, 3, 4}
+		return &matrixResponse{Distance: matrixRows{row[:len(body.Locations)]}, Time: matrixRows{row[:len(body.Locations)]}}, nil
+	}
+	points := []LatLng{{Lat: 0, Lng: 0.01}, {Lat: 0, Lng: 0.02}, {Lat: 0, Lng: 0.03}, {Lat: 0, Lng: 5}}
+	index := NewPointIndex(points, Kilometers)
+	neighbors, err := index.NearestByRoute(LatLng{}, 2, 3, Fastest)
+	if unexpected(err, t) {
+		return
+	}
+	if len(neighbors) != 2 || neighbors[0].Index != 1 || neighbors[0].Distance != 3 || neighbors[1].Index != 2 || neighbors[1].Time != 4 {
+		t.Errorf("Unexpected neighbors %+v", neighbors)
+	}
+	// unroutable points (a distance of 0 or less) are left out, except at the point itself
+	postRouteMatrix = func(body matrixBody) (*matrixResponse, error) {
+		row := []float64{0, 0, -1, 7}
+		return &matrixResponse{Distance: matrixRows{row}, Time: matrixRows{row}}, nil
+	}
+	points = []LatLng{{Lat: 0, Lng: 0.01}, {Lat: 0, Lng: 0.02}, {}, {Lat: 0, Lng: 5}}
+	neighbors, err = NewPointIndex(points, Kilometers).NearestByRoute(LatLng{}, 3, 3, Fastest)
+	if unexpected(err, t) {
+		return
+	}
+	if len(neighbors) != 2 || neighbors[0].Index != 2 || neighbors[0].Distance != 0 || neighbors[1].Index != 1 || neighbors[1].Distance != 7 {
+		t.Errorf("Unexpected neighbors %+v", neighbors)
+	}
+}
+
+func TestNeighborFormat(t *testing.T) {
+	neighbor := Neighbor{Index: 3, LatLng: LatLng{Lat: 51.2, Lng: 4.4}, Distance: 1.5}
+	if formatted := fmt.Sprintf("%+v", neighbor); formatted != "{Index:3 LatLng:51.2,4.4 Distance:1.5 Time:0}" {
+		t.Errorf("Expected {Index:3 LatLng:51.2,4.4 Distance:1.5 Time:0} ~ Received %s", formatted)
+	}
+}
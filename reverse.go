@@ -0,0 +1,152 @@
+package geocoder
+
+import (
+	"container/list"
+	"errors"
+	"sync"
+)
+
+// ReverseGeocoder returns the address of a point
+type ReverseGeocoder interface {
+	ReverseGeocode(point LatLng) (*Location, error)
+}
+
+// ReverseGeocoderFunc adapts a function to a ReverseGeocoder
+type ReverseGeocoderFunc func(point LatLng) (*Location, error)
+
+// ReverseGeocode calls fn(point)
+func (fn ReverseGeocoderFunc) ReverseGeocode(point LatLng) (*Location, error) {
+	return fn(point)
+}
+
+// MapquestReverseGeocoder reverse geocodes with the mapquest api (see ReverseGeocode)
+var MapquestReverseGeocoder ReverseGeocoder = ReverseGeocoderFunc(func(point LatLng) (*Location, error) {
+	return ReverseGeocode(point.Lat, point.Lng)
+})
+
+// default geohash precision of the ReverseGeocodeCache keys, about 150 meters
+const reverseGeocodePrecision = 7
+
+// ReverseGeocodeCache caches the locations of a ReverseGeocoder by geohash,
+// so lookups of nearby points share a single request. The least recently
+// used locations are evicted. Concurrent lookups of the same geohash wait for
+// a single request. A ReverseGeocodeCache is safe for concurrent use, the zero
+// value caches MapquestReverseGeocoder lookups without a size limit.
+type ReverseGeocodeCache struct {
+	// Geocoder to look up the points which are not cached (default MapquestReverseGeocoder)
+	Geocoder ReverseGeocoder
+	// Geohash precision of the keys, 7 characters is about 150 meters (default)
+	Precision int
+	// Maximum number of cached locations (0: no limit)
+	Size  int
+	mutex sync.Mutex
+	// entries by geohash, and the geohashes from most to least recently used
+	entries map[string]*list.Element
+	order   *list.List
+	// lookups in flight by geohash
+	calls map[string]*reverseGeocodeCall
+}
+
+// reverseGeocodeEntry is a cached location
+type reverseGeocodeEntry struct {
+	hash     string
+	location Location
+}
+
+// reverseGeocodeCall is a lookup in flight
+type reverseGeocodeCall struct {
+	done     sync.WaitGroup
+	location *Location
+	err      error
+}
+
+// result returns a copy of the looked up location
+func (call *reverseGeocodeCall) result() (*Location, error) {
+	if call.err != nil {
+		return nil, call.err
+	}
+	location := *call.location
+	return &location, nil
+}
+
+// NewReverseGeocodeCache is a constructor to initialize a ReverseGeocodeCache
+// of 10000 locations for a geocoder, eg MapquestReverseGeocoder.
+func NewReverseGeocodeCache(geocoder ReverseGeocoder) *ReverseGeocodeCache {
+	return &ReverseGeocodeCache{
+		Geocoder:  geocoder,
+		Precision: reverseGeocodePrecision,
+		Size:      10000,
+	}
+}
+
+// ReverseGeocode returns the cached location of the geohash of the point,
+// looking it up if needed. Errors are not cached.
+func (cache *ReverseGeocodeCache) ReverseGeocode(point LatLng) (*Location, error) {
+	precision := cache.Precision
+	if precision <= 0 {
+		precision = reverseGeocodePrecision
+	}
+	hash := point.Geohash(precision)
+	cache.mutex.Lock()
+	if cache.entries == nil {
+		cache.entries = map[string]*list.Element{}
+		cache.order = list.New()
+		cache.calls = map[string]*reverseGeocodeCall{}
+	}
+	if element, ok := cache.entries[hash]; ok {
+		cache.order.MoveToFront(element)
+		location := element.Value.(*reverseGeocodeEntry).location
+		cache.mutex.Unlock()
+		return &location, nil
+	}
+	if call, ok := cache.calls[hash]; ok {
+		cache.mutex.Unlock()
+		call.done.Wait()
+		return call.result()
+	}
+	call := &reverseGeocodeCall{}
+	call.done.Add(1)
+	cache.calls[hash] = call
+	cache.mutex.Unlock()
+	cache.lookup(call, hash, point)
+	return call.result()
+}
+
+// lookup reverse geocodes the point of an in flight call and caches the
+// location. The call is finished and removed even if the geocoder panics.
+func (cache *ReverseGeocodeCache) lookup(call *reverseGeocodeCall, hash string, point LatLng) {
+	defer func() {
+		cache.mutex.Lock()
+		delete(cache.calls, hash)
+		if call.err == nil {
+			cache.entries[hash] = cache.order.PushFront(&reverseGeocodeEntry{hash: hash, location: *call.location})
+			for cache.Size > 0 && cache.order.Len() > cache.Size {
+				oldest := cache.order.Back()
+				cache.order.Remove(oldest)
+				delete(cache.entries, oldest.Value.(*reverseGeocodeEntry).hash)
+			}
+		}
+		cache.mutex.Unlock()
+		call.done.Done()
+	}()
+	// reported to the waiting lookups if the geocoder panics
+	call.err = errors.New("Reverse geocoder panicked")
+	geocoder := cache.Geocoder
+	if geocoder == nil {
+		geocoder = MapquestReverseGeocoder
+	}
+	call.location, call.err = geocoder.ReverseGeocode(point)
+	if call.err == nil && call.location == nil {
+		call.err = errors.New("Reverse geocoder returned no location")
+	}
+}
+
+// Len returns the number of cached locations
+func (cache *ReverseGeocodeCache) Len() int {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	if cache.order == nil {
+		return 0
+	}
+	return cache.order.Len()
+}
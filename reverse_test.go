@@ -0,0 +1,122 @@
+package geocoder
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestReverseGeocodeCache(t *testing.T) {
+	lookups := 0
+	fail := false
+	cache := NewReverseGeocodeCache(ReverseGeocoderFunc(func(point LatLng) (*Location, error) {
+		if fail {
+			return nil, errors.New("Error looking up")
+		}
+		lookups++
+		return &Location{Street: "Damrak", LatLng: point}, nil
+	}))
+	cache.Size = 2
+	first, err := cache.ReverseGeocode(LatLng{Lat: 52.37510, Lng: 4.89590})
+	if unexpected(err, t) {
+		return
+	}
+	first.Street = "changed"
+	// a few meters away, same geohash cell
+	second, err := cache.ReverseGeocode(LatLng{Lat: 52.37511, Lng: 4.89591})
+	if unexpected(err, t) {
+		return
+	}
+	if lookups != 1 || second.Street != "Damrak" {
+		t.Errorf("Expected 1 lookup of Damrak ~ Received %v of %v", lookups, second.Street)
+	}
+	cache.ReverseGeocode(LatLng{Lat: 48.8566, Lng: 2.3522})
+	cache.ReverseGeocode(LatLng{Lat: 51.5074, Lng: -0.1278})
+	if cache.Len() != 2 {
+		t.Errorf("Expected 2 ~ Received %v", cache.Len())
+	}
+	// the least recently used location (Damrak) was evicted
+	cache.ReverseGeocode(LatLng{Lat: 52.37510, Lng: 4.89590})
+	if lookups != 4 {
+		t.Errorf("Expected 4 lookups ~ Received %v", lookups)
+	}
+	fail = true
+	if _, err := cache.ReverseGeocode(LatLng{Lat: 40.7128, Lng: -74.0060}); err == nil {
+		t.Errorf("Expected an error")
+	}
+	if cache.Len() != 2 {
+		t.Errorf("Expected errors not to be cached ~ Received %v", cache.Len())
+	}
+}
+
+func TestReverseGeocodeCacheConcurrent(t *testing.T) {
+	var lookups int32
+	release := make(chan struct{})
+	cache := &ReverseGeocodeCache{Geocoder: ReverseGeocoderFunc(func(point LatLng) (*Location, error) {
+		atomic.AddInt32(&lookups, 1)
+		<-release
+		return &Location{City: "Amsterdam", LatLng: point}, nil
+	})}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			location, err := cache.ReverseGeocode(LatLng{Lat: 52.37510, Lng: 4.89590})
+			if !unexpected(err, t) && location.City != "Amsterdam" {
+				t.Errorf("Expected Amsterdam ~ Received %v", location.City)
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if atomic.LoadInt32(&lookups) != 1 || cache.Len() != 1 {
+		t.Errorf("Expected a single lookup ~ Received %d (%d cached)", lookups, cache.Len())
+	}
+}
+
+func TestReverseGeocodeCachePanic(t *testing.T) {
+	cache := &ReverseGeocodeCache{Geocoder: ReverseGeocoderFunc(func(point LatLng) (*Location, error) {
+		panic("lookup failed")
+	})}
+	func() {
+		defer func() { recover() }()
+		cache.ReverseGeocode(LatLng{Lat: 52.37510, Lng: 4.89590})
+		t.Errorf("Expected the panic to reach the caller")
+	}()
+	cache.Geocoder = ReverseGeocoderFunc(func(point LatLng) (*Location, error) {
+		return &Location{City: "Amsterdam"}, nil
+	})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if location, err := cache.ReverseGeocode(LatLng{Lat: 52.37510, Lng: 4.89590}); !unexpected(err, t) && location.City != "Amsterdam" {
+			t.Errorf("Expected Amsterdam ~ Received %v", location.City)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Errorf("Expected the lookup after a panic not to block")
+	}
+}
+
+func TestReverseGeocodeCacheZeroValue(t *testing.T) {
+	var cache ReverseGeocodeCache
+	if cache.Len() != 0 {
+		t.Errorf("Expected an empty cache")
+	}
+	cache.Geocoder = ReverseGeocoderFunc(func(point LatLng) (*Location, error) {
+		return &Location{City: "Amsterdam"}, nil
+	})
+	if location, err := cache.ReverseGeocode(LatLng{Lat: 52.37510, Lng: 4.89590}); !unexpected(err, t) && location.City != "Amsterdam" {
+		t.Errorf("Expected Amsterdam ~ Received %v", location.City)
+	}
+	// default precision of 7 characters
+	if _, ok := cache.entries[LatLng{Lat: 52.37510, Lng: 4.89590}.Geohash(7)]; !ok || cache.Len() != 1 {
+		t.Errorf("Expected a 7 character key ~ Received %v", cache.entries)
+	}
+}
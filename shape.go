@@ -0,0 +1,232 @@
+package geocoder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// Shape is the route shape (polyline) as returned by the directions api.
+// The shape points can be requested raw (default) or compressed (cmp, cmp6);
+// either way they are decoded into Points.
+type Shape struct {
+	// Shape point index at which each leg starts
+	LegIndexes []int `json:"legIndexes"`
+	// Shape point index at which each maneuver starts (over all legs)
+	ManeuverIndexes []int `json:"maneuverIndexes"`
+	// Decoded shape points
+	Points []LatLng `json:"-"`
+	// compressed shape points which still need to be decoded
+	encoded string
+}
+
+// shapeJSON is the wire format of a shape.
+type shapeJSON struct {
+	LegIndexes      []int           `json:"legIndexes"`
+	ManeuverIndexes []int           `json:"maneuverIndexes"`
+	ShapePoints     json.RawMessage `json:"shapePoints"`
+}
+
+// UnmarshalJSON decodes raw shape points ([lat, lng, lat, lng, ...]) directly.
+// Compressed shape points are kept until their precision is known.
+func (shape *Shape) UnmarshalJSON(data []byte) error {
+	var raw shapeJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	shape.LegIndexes = raw.LegIndexes
+	shape.ManeuverIndexes = raw.ManeuverIndexes
+	shape.Points = nil
+	shape.encoded = ""
+	points := bytes.TrimSpace(raw.ShapePoints)
+	if len(points) == 0 || bytes.Equal(points, []byte("null")) {
+		return nil
+	}
+	if points[0] == '"' {
+		return json.Unmarshal(points, &shape.encoded)
+	}
+	var values []float64
+	if err := json.Unmarshal(points, &values); err != nil {
+		return err
+	}
+	if len(values)%2 != 0 {
+		return fmt.Errorf("Odd number of shape point values: %d", len(values))
+	}
+	shape.Points = make([]LatLng, len(values)/2)
+	for i := range shape.Points {
+		shape.Points[i] = LatLng{Lat: values[2*i], Lng: values[2*i+1]}
+	}
+	return nil
+}
+
+// MarshalJSON encodes the shape with raw shape points.
+func (shape Shape) MarshalJSON() ([]byte, error) {
+	values := make([]float64, 0, 2*len(shape.Points))
+	for _, point := range shape.Points {
+		values = append(values, point.Lat, point.Lng)
+	}
+	points, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(shapeJSON{
+		LegIndexes:      nonNilInts(shape.LegIndexes),
+		ManeuverIndexes: nonNilInts(shape.ManeuverIndexes),
+		ShapePoints:     points,
+	})
+}
+
+// decompress decodes compressed shape points (if any) with the given precision.
+func (shape *Shape) decompress(precision int) (err error) {
+	if shape.encoded == "" {
+		return
+	}
+	shape.Points, err = DecodeShape(shape.encoded, precision)
+	if err == nil {
+		shape.encoded = ""
+	}
+	return
+}
+
+func nonNilInts(ints []int) []int {
+	if ints == nil {
+		return []int{}
+	}
+	return ints
+}
+
+// shapePrecision returns the number of decimals used by a shape format
+func shapePrecision(format ShapeFormat) int {
+	if format == ShapeCmp6 {
+		return 6
+	}
+	return 5
+}
+
+// EncodeShape compresses points with the mapquest compressed lat/lng
+// encoding algorithm. Use precision 5 for "cmp" and 6 for "cmp6".
+func EncodeShape(points []LatLng, precision int) string {
+	var (
+		encoded          bytes.Buffer
+		prevLat, prevLng int64
+	)
+	factor := math.Pow10(precision)
+	writeValue := func(value int64) {
+		value <<= 1
+		if value < 0 {
+			value = ^value
+		}
+		for value >= 0x20 {
+			encoded.WriteByte(byte((0x20 | (value & 0x1f)) + 63))
+			value >>= 5
+		}
+		encoded.WriteByte(byte(value + 63))
+	}
+	for _, point := range points {
+		lat := int64(math.Round(point.Lat * factor))
+		lng := int64(math.Round(point.Lng * factor))
+		writeValue(lat - prevLat)
+		writeValue(lng - prevLng)
+		prevLat, prevLng = lat, lng
+	}
+	return encoded.String()
+}
+
+// DecodeShape decompresses a shape which was compressed with the mapquest
+// compressed lat/lng encoding algorithm. Use precision 5 for "cmp" and 6 for "cmp6".
+func DecodeShape(encoded string, precision int) ([]LatLng, error) {
+	var (
+		points   []LatLng
+		lat, lng int64
+		index    int
+	)
+	factor := math.Pow10(precision)
+	readValue := func() (int64, error) {
+		var result int64
+		var shift uint
+		for {
+			if index >= len(encoded) {
+				return 0, fmt.Errorf("Truncated shape at position %d", index)
+			}
+			b := int64(encoded[index]) - 63
+			index++
+			if b < 0 || b > 0x3f {
+				return 0, fmt.Errorf("Invalid shape character at position %d", index-1)
+			}
+			result |= (b & 0x1f) << shift
+			shift += 5
+			if b < 0x20 {
+				break
+			}
+		}
+		if result&1 != 0 {
+			return ^(result >> 1), nil
+		}
+		return result >> 1, nil
+	}
+	for index < len(encoded) {
+		dLat, err := readValue()
+		if err != nil {
+			return nil, err
+		}
+		dLng, err := readValue()
+		if err != nil {
+			return nil, err
+		}
+		lat += dLat
+		lng += dLng
+		points = append(points, LatLng{Lat: float64(lat) / factor, Lng: float64(lng) / factor})
+	}
+	return points, nil
+}
+
+// slice returns the shape points from start up to and including end.
+// An end beyond the last point is clipped.
+func (shape Shape) slice(start, end int) []LatLng {
+	n := len(shape.Points)
+	if start < 0 || start >= n {
+		return nil
+	}
+	if end >= n || end < start {
+		end = n - 1
+	}
+	return shape.Points[start : end+1]
+}
+
+// LegShape returns the shape points of a leg (zero based index).
+// The shape is only available when it was requested (eg with FullShape).
+func (route Route) LegShape(leg int) []LatLng {
+	// a route of n legs has n+1 leg indexes
+	indexes := route.Shape.LegIndexes
+	if leg < 0 || leg >= len(route.Legs) || leg >= len(indexes) {
+		return nil
+	}
+	end := len(route.Shape.Points) - 1
+	if leg+1 < len(indexes) {
+		end = indexes[leg+1]
+	}
+	return route.Shape.slice(indexes[leg], end)
+}
+
+// ManeuverShape returns the shape points of a maneuver (zero based index)
+// within a leg (zero based index), from its start point up to
+// the start point of the next maneuver.
+func (route Route) ManeuverShape(leg, maneuver int) []LatLng {
+	if leg < 0 || leg >= len(route.Legs) || maneuver < 0 || maneuver >= len(route.Legs[leg].Maneuvers) {
+		return nil
+	}
+	k := maneuver
+	for _, previous := range route.Legs[:leg] {
+		k += len(previous.Maneuvers)
+	}
+	indexes := route.Shape.ManeuverIndexes
+	if k >= len(indexes) {
+		return nil
+	}
+	end := len(route.Shape.Points) - 1
+	if k+1 < len(indexes) {
+		end = indexes[k+1]
+	}
+	return route.Shape.slice(indexes[k], end)
+}
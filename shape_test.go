@@ -0,0 +1,96 @@
+package geocoder
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const (
+	testEncodedShape = "_p~iF~ps|U_ulLnnqC_mqNvxq`@"
+	testShapeJSON    = `{"legIndexes":[0,4],"maneuverIndexes":[0,2,4],"shapePoints":[51.529315,-0.269962,51.529087,-0.271016,51.52901,-0.271373,51.52885,-0.272208,51.528568,-0.274354]}`
+)
+
+var testShapePoints = []LatLng{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}}
+
+func TestEncodeShape(t *testing.T) {
+	encoded := EncodeShape(testShapePoints, 5)
+	if encoded != testEncodedShape {
+		t.Errorf("Expected %s ~ Received %s", testEncodedShape, encoded)
+	}
+}
+
+func TestDecodeShape(t *testing.T) {
+	for _, precision := range []int{5, 6} {
+		points, err := DecodeShape(EncodeShape(testShapePoints, precision), precision)
+		if unexpected(err, t) {
+			return
+		}
+		if len(points) != len(testShapePoints) {
+			t.Fatalf("Precision %d: Expected %d points ~ Received %d", precision, len(testShapePoints), len(points))
+		}
+		for i, point := range points {
+			if point != testShapePoints[i] {
+				t.Errorf("Precision %d: Expected %v ~ Received %v", precision, testShapePoints[i], point)
+			}
+		}
+	}
+	if _, err := DecodeShape("_p~iF~ps|", 5); err == nil {
+		t.Errorf("Truncated shape: Expected error ~ Received nil")
+	}
+}
+
+func TestShapeJSON(t *testing.T) {
+	var shape Shape
+	if err := json.Unmarshal([]byte(testShapeJSON), &shape); unexpected(err, t) {
+		return
+	}
+	if len(shape.Points) != 5 || shape.Points[1] != (LatLng{51.529087, -0.271016}) {
+		t.Errorf("Shape.Points: Received %v", shape.Points)
+	}
+	data, err := json.Marshal(shape)
+	if unexpected(err, t) {
+		return
+	}
+	if string(data) != testShapeJSON {
+		t.Errorf("Expected\n%s\nReceived\n%s", testShapeJSON, data)
+	}
+}
+
+func TestCompressedShapeJSON(t *testing.T) {
+	var shape Shape
+	err := json.Unmarshal([]byte(`{"legIndexes":[0],"maneuverIndexes":[0],"shapePoints":"`+testEncodedShape+`"}`), &shape)
+	if unexpected(err, t) {
+		return
+	}
+	if shape.Points != nil {
+		t.Errorf("Shape.Points: Expected nil before decompress ~ Received %v", shape.Points)
+	}
+	if err = shape.decompress(shapePrecision("cmp")); unexpected(err, t) {
+		return
+	}
+	if len(shape.Points) != 3 || shape.Points[2] != testShapePoints[2] {
+		t.Errorf("Shape.Points: Expected %v ~ Received %v", testShapePoints, shape.Points)
+	}
+}
+
+func TestManeuverShape(t *testing.T) {
+	var route Route
+	if err := json.Unmarshal([]byte(`{"legs":[{"maneuvers":[{},{},{}]}],"shape":`+testShapeJSON+`}`), &route); unexpected(err, t) {
+		return
+	}
+	if n := len(route.LegShape(0)); n != 5 {
+		t.Errorf("LegShape(0): Expected 5 points ~ Received %d", n)
+	}
+	if shape := route.LegShape(1); shape != nil {
+		t.Errorf("LegShape(1): Expected nil ~ Received %v", shape)
+	}
+	if n := len(route.ManeuverShape(0, 0)); n != 3 {
+		t.Errorf("ManeuverShape(0, 0): Expected 3 points ~ Received %d", n)
+	}
+	if n := len(route.ManeuverShape(0, 2)); n != 1 {
+		t.Errorf("ManeuverShape(0, 2): Expected 1 point ~ Received %d", n)
+	}
+	if route.ManeuverShape(1, 0) != nil {
+		t.Errorf("ManeuverShape(1, 0): Expected nil")
+	}
+}
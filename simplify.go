@@ -0,0 +1,242 @@
+/* Simplifies and resamples route shapes, eg to send lighter shapes to mobile clients.
+
+Tolerances and spacings are in meters. Shape.Simplify and Shape.Resample keep
+the start points of all legs and maneuvers and update LegIndexes and
+ManeuverIndexes accordingly.
+
+Example:
+
+results, err := directions.Get()
+results.Route.Shape = results.Route.Shape.Simplify(10, DouglasPeucker)
+
+*/
+
+package geocoder
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+// SimplifyAlgorithm selects how shape points are simplified
+type SimplifyAlgorithm int
+
+// Simplification algorithms
+const (
+	// Douglas-Peucker keeps the points further than the tolerance
+	// from the simplified line.
+	DouglasPeucker SimplifyAlgorithm = iota
+	// Visvalingam-Whyatt removes the points with the smallest effective area
+	// as long as that area is below tolerance² (square meters).
+	Visvalingam
+)
+
+// segmentDistance returns the distance (meters) of point to the great circle
+// segment from start to end.
+func segmentDistance(point, start, end LatLng) float64 {
+	if start == end {
+		return haversine(start, point)
+	}
+	cross, along := crossTrack(point, start, end)
+	switch {
+	case along < 0:
+		return haversine(start, point)
+	case along > angularDistance(start, end):
+		return haversine(end, point)
+	}
+	return math.Abs(cross) * earthRadius
+}
+
+// triangleArea returns the area (square meters) of a small triangle,
+// using an equirectangular projection around b.
+func triangleArea(a, b, c LatLng) float64 {
+	scale := math.Cos(radians(b.Lat))
+	ax, ay := radians(a.Lng-b.Lng)*scale, radians(a.Lat-b.Lat)
+	cx, cy := radians(c.Lng-b.Lng)*scale, radians(c.Lat-b.Lat)
+	return math.Abs(ax*cy-ay*cx) / 2 * earthRadius * earthRadius
+}
+
+// douglasPeucker marks the points to keep between first and last
+func douglasPeucker(points []LatLng, tolerance float64, keep []bool) {
+	stack := [][2]int{{0, len(points) - 1}}
+	for len(stack) > 0 {
+		first, last := stack[len(stack)-1][0], stack[len(stack)-1][1]
+		stack = stack[:len(stack)-1]
+		farthest, maxDistance := -1, tolerance
+		for i := first + 1; i < last; i++ {
+			if distance := segmentDistance(points[i], points[first], points[last]); distance > maxDistance {
+				farthest, maxDistance = i, distance
+			}
+		}
+		if farthest >= 0 {
+			keep[farthest] = true
+			stack = append(stack, [2]int{first, farthest}, [2]int{farthest, last})
+		}
+	}
+}
+
+// visvalingamPoint is a point in the heap of effective areas
+type visvalingamPoint struct {
+	index int
+	area  float64
+}
+
+type visvalingamHeap []visvalingamPoint
+
+func (h visvalingamHeap) Len() int            { return len(h) }
+func (h visvalingamHeap) Less(i, j int) bool  { return h[i].area < h[j].area }
+func (h visvalingamHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *visvalingamHeap) Push(x interface{}) { *h = append(*h, x.(visvalingamPoint)) }
+func (h *visvalingamHeap) Pop() interface{} {
+	old := *h
+	point := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return point
+}
+
+// visvalingam marks the points to keep between first and last
+func visvalingam(points []LatLng, tolerance float64, keep []bool) {
+	n := len(points)
+	previous, next := make([]int, n), make([]int, n)
+	areas := make([]float64, n)
+	h := &visvalingamHeap{}
+	for i := range points {
+		previous[i], next[i] = i-1, i+1
+		if i > 0 && i < n-1 {
+			areas[i] = triangleArea(points[i-1], points[i], points[i+1])
+			heap.Push(h, visvalingamPoint{index: i, area: areas[i]})
+		}
+		keep[i] = true
+	}
+	threshold := tolerance * tolerance
+	for h.Len() > 0 {
+		point := heap.Pop(h).(visvalingamPoint)
+		i := point.index
+		if !keep[i] || point.area != areas[i] {
+			// removed or outdated
+			continue
+		}
+		if point.area >= threshold {
+			break
+		}
+		keep[i] = false
+		p, q := previous[i], next[i]
+		next[p], previous[q] = q, p
+		// a neighbour's effective area is at least the area just removed
+		for _, j := range []int{p, q} {
+			if j > 0 && j < n-1 {
+				areas[j] = math.Max(point.area, triangleArea(points[previous[j]], points[j], points[next[j]]))
+				heap.Push(h, visvalingamPoint{index: j, area: areas[j]})
+			}
+		}
+	}
+}
+
+// Simplify returns the points that remain after simplification with a
+// tolerance in meters. The first and last points are always kept.
+func Simplify(points []LatLng, tolerance float64, algorithm SimplifyAlgorithm) []LatLng {
+	if len(points) < 3 {
+		return append([]LatLng(nil), points...)
+	}
+	keep := make([]bool, len(points))
+	if algorithm == Visvalingam {
+		visvalingam(points, tolerance, keep)
+	} else {
+		douglasPeucker(points, tolerance, keep)
+	}
+	keep[0], keep[len(points)-1] = true, true
+	var simplified []LatLng
+	for i, point := range points {
+		if keep[i] {
+			simplified = append(simplified, point)
+		}
+	}
+	return simplified
+}
+
+// Resample returns points spaced evenly (spacing in meters) along the
+// great circle segments of the line. The first and last points are kept,
+// the last spacing may be shorter.
+func Resample(points []LatLng, spacing float64) []LatLng {
+	if len(points) < 2 || spacing <= 0 {
+		return append([]LatLng(nil), points...)
+	}
+	resampled := []LatLng{points[0]}
+	// distance along the line since the last resampled point
+	since := 0.0
+	for i := 1; i < len(points); i++ {
+		start, end := points[i-1], points[i]
+		length := haversine(start, end)
+		bearing := start.Bearing(end)
+		offset := spacing - since
+		for ; offset < length; offset += spacing {
+			resampled = append(resampled, destination(start, bearing, offset))
+		}
+		since = length - offset + spacing
+	}
+	if last := points[len(points)-1]; resampled[len(resampled)-1] != last {
+		resampled = append(resampled, last)
+	}
+	return resampled
+}
+
+// transform applies fn to the parts of the shape between the start points
+// of legs and maneuvers, and remaps the leg and maneuver indexes.
+func (shape Shape) transform(fn func(part []LatLng) []LatLng) Shape {
+	n := len(shape.Points)
+	result := Shape{
+		LegIndexes:      append([]int(nil), shape.LegIndexes...),
+		ManeuverIndexes: append([]int(nil), shape.ManeuverIndexes...),
+	}
+	if n == 0 {
+		return result
+	}
+	fixed := map[int]int{0: 0, n - 1: 0}
+	for _, indexes := range [][]int{shape.LegIndexes, shape.ManeuverIndexes} {
+		for _, index := range indexes {
+			if index >= 0 && index < n {
+				fixed[index] = 0
+			}
+		}
+	}
+	starts := make([]int, 0, len(fixed))
+	for index := range fixed {
+		starts = append(starts, index)
+	}
+	sort.Ints(starts)
+	result.Points = []LatLng{shape.Points[0]}
+	for i := 1; i < len(starts); i++ {
+		part := fn(shape.Points[starts[i-1] : starts[i]+1])
+		// the first point of the part is the last point of the previous part
+		result.Points = append(result.Points, part[1:]...)
+		fixed[starts[i]] = len(result.Points) - 1
+	}
+	for _, indexes := range [][]int{result.LegIndexes, result.ManeuverIndexes} {
+		for i, index := range indexes {
+			if index >= n {
+				index = n - 1
+			}
+			if newIndex, ok := fixed[index]; ok {
+				indexes[i] = newIndex
+			}
+		}
+	}
+	return result
+}
+
+// Simplify returns the shape simplified with a tolerance in meters.
+// The start points of legs and maneuvers are kept.
+func (shape Shape) Simplify(tolerance float64, algorithm SimplifyAlgorithm) Shape {
+	return shape.transform(func(part []LatLng) []LatLng {
+		return Simplify(part, tolerance, algorithm)
+	})
+}
+
+// Resample returns the shape resampled with a spacing in meters.
+// The start points of legs and maneuvers are kept.
+func (shape Shape) Resample(spacing float64) Shape {
+	return shape.transform(func(part []LatLng) []LatLng {
+		return Resample(part, spacing)
+	})
+}
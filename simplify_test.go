@@ -0,0 +1,86 @@
+package geocoder
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+// zigzag returns points eastwards along the equator, every other point
+// offset north by offset meters.
+func zigzag(n int, offset float64) []LatLng {
+	points := make([]LatLng, n)
+	for i := range points {
+		points[i] = destination(LatLng{}, 90, float64(i)*1000)
+		if i%2 == 1 {
+			points[i] = destination(points[i], 0, offset)
+		}
+	}
+	return points
+}
+
+func TestSimplify(t *testing.T) {
+	// east for 5 km, then north for 5 km, with 5 meters of noise
+	points := zigzag(6, 5)
+	for i := 1; i <= 5; i++ {
+		point := destination(points[5], 0, float64(i)*1000)
+		if i%2 == 1 {
+			point = destination(point, 90, 5)
+		}
+		points = append(points, point)
+	}
+	tolerances := map[SimplifyAlgorithm]float64{DouglasPeucker: 20, Visvalingam: 150}
+	for algorithm, tolerance := range tolerances {
+		simplified := Simplify(points, tolerance, algorithm)
+		expected := []LatLng{points[0], points[5], points[10]}
+		if !reflect.DeepEqual(simplified, expected) {
+			t.Errorf("%d: Expected %v ~ Received %v", algorithm, expected, simplified)
+		}
+		if simplified = Simplify(points, 1, algorithm); len(simplified) != len(points) {
+			t.Errorf("%d: Expected %d points ~ Received %d", algorithm, len(points), len(simplified))
+		}
+	}
+}
+
+func TestSegmentDistance(t *testing.T) {
+	start, end := LatLng{}, destination(LatLng{}, 90, 1000)
+	if distance := segmentDistance(destination(start, 0, 10), start, end); math.Abs(distance-10) > 1e-6 {
+		t.Errorf("Expected 10 ~ Received %f", distance)
+	}
+	if distance := segmentDistance(destination(start, 270, 30), start, end); math.Abs(distance-30) > 1e-6 {
+		t.Errorf("Expected 30 ~ Received %f", distance)
+	}
+}
+
+func TestResample(t *testing.T) {
+	points := []LatLng{{}, destination(LatLng{}, 90, 250), destination(LatLng{}, 90, 1050)}
+	resampled := Resample(points, 100)
+	if len(resampled) != 12 || resampled[11] != points[2] {
+		t.Errorf("Expected 12 points ending at %v ~ Received %v", points[2], resampled)
+		return
+	}
+	for i := 1; i < 11; i++ {
+		if distance := haversine(resampled[i-1], resampled[i]); math.Abs(distance-100) > 1e-6 {
+			t.Errorf("%d: Expected a spacing of 100 ~ Received %f", i, distance)
+		}
+	}
+}
+
+func TestShapeSimplify(t *testing.T) {
+	shape := Shape{Points: zigzag(11, 5), LegIndexes: []int{0}, ManeuverIndexes: []int{0, 3, 10}}
+	simplified := shape.Simplify(20, DouglasPeucker)
+	expected := []LatLng{shape.Points[0], shape.Points[3], shape.Points[10]}
+	if !reflect.DeepEqual(simplified.Points, expected) {
+		t.Errorf("Expected %v ~ Received %v", expected, simplified.Points)
+	}
+	if !reflect.DeepEqual(simplified.ManeuverIndexes, []int{0, 1, 2}) || !reflect.DeepEqual(simplified.LegIndexes, []int{0}) {
+		t.Errorf("Expected maneuver indexes [0 1 2] ~ Received %v", simplified.ManeuverIndexes)
+	}
+	resampled := shape.Resample(500)
+	if index := resampled.ManeuverIndexes[1]; resampled.Points[index] != shape.Points[3] {
+		t.Errorf("Expected maneuver start %v ~ Received %v", shape.Points[3], resampled.Points[index])
+	}
+	if last := resampled.ManeuverIndexes[2]; last != len(resampled.Points)-1 {
+		t.Errorf("Expected the last maneuver at %d ~ Received %d", len(resampled.Points)-1, last)
+	}
+}
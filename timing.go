@@ -0,0 +1,84 @@
+package geocoder
+
+import "time"
+
+// TimeType specifies to which moment the route time applies
+type TimeType int
+
+// Time types
+const (
+	TimeNone      TimeType = 0
+	TimeCurrent   TimeType = 1
+	TimeDeparture TimeType = 2
+	TimeArrival   TimeType = 3
+)
+
+// mapquest date and local time formats
+const (
+	directionsDateFormat = "01/02/2006"
+	directionsTimeFormat = "15:04"
+)
+
+// timeType returns the TimeType, derived from DepartureTime
+// or ArrivalTime when not set explicitly.
+func (directions Directions) timeType() TimeType {
+	switch {
+	case directions.TimeType != TimeNone:
+		return directions.TimeType
+	case !directions.DepartureTime.IsZero():
+		return TimeDeparture
+	case !directions.ArrivalTime.IsZero():
+		return TimeArrival
+	}
+	return TimeNone
+}
+
+// localTime returns the departure or arrival time in the time zone of
+// its location (TimeZone) as mapquest expects a local date and time.
+func (directions Directions) localTime() (moment time.Time, ok bool) {
+	switch directions.timeType() {
+	case TimeDeparture:
+		moment = directions.DepartureTime
+	case TimeArrival:
+		moment = directions.ArrivalTime
+	default:
+		return
+	}
+	if directions.TimeZone != nil {
+		moment = moment.In(directions.TimeZone)
+	}
+	return moment, true
+}
+
+// validateTime adds the errors of invalid time options
+func (directions Directions) validateTime(v *validator) {
+	v.check(directions.DepartureTime.IsZero() || directions.ArrivalTime.IsZero(),
+		"DepartureTime and ArrivalTime are exclusive")
+	v.check(directions.TimeType >= TimeNone && directions.TimeType <= TimeArrival,
+		"TimeType %d is not within [0..3]", directions.TimeType)
+	v.check(directions.TimeType != TimeDeparture || !directions.DepartureTime.IsZero(),
+		"TimeType %d needs a DepartureTime", directions.TimeType)
+	v.check(directions.TimeType != TimeArrival || !directions.ArrivalTime.IsZero(),
+		"TimeType %d needs an ArrivalTime", directions.TimeType)
+}
+
+// duration returns the real time (traffic aware) of the route if known,
+// otherwise the calculated elapsed time.
+func (route Route) duration() time.Duration {
+	if route.RealTime > 0 {
+		return time.Duration(route.RealTime) * time.Second
+	}
+	return time.Duration(route.Time) * time.Second
+}
+
+// ETA returns the estimated time of arrival when departing at departure.
+// The real time (with traffic) is used when available.
+func (route Route) ETA(departure time.Time) time.Time {
+	return departure.Add(route.duration())
+}
+
+// Departure returns the latest departure time to arrive at arrival.
+// The real time (with traffic) is used when available.
+func (route Route) Departure(arrival time.Time) time.Time {
+	return arrival.Add(-route.duration())
+}
@@ -0,0 +1,61 @@
+package geocoder
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDepartureTime(t *testing.T) {
+	brussels, err := time.LoadLocation("Europe/Brussels")
+	if err != nil {
+		t.Skipf("No time zone database: %v", err)
+	}
+	directions := NewDirections("Amsterdam,Netherlands", []string{"Antwerp,Belgium"})
+	directions.DepartureTime = time.Date(2026, 3, 9, 6, 30, 0, 0, time.UTC)
+	directions.TimeZone = brussels
+	directions.UseTraffic = true
+	if err = directions.Validate(); unexpected(err, t) {
+		return
+	}
+	routeURL := directions.URL("json")
+	if !strings.Contains(routeURL, "&timeType=2&dateType=0&date=03%2F09%2F2026&localTime=07%3A30&useTraffic=true") {
+		t.Errorf("Expected local departure time in %s", routeURL)
+	}
+	body, err := json.Marshal(directions.body())
+	if unexpected(err, t) {
+		return
+	}
+	if !strings.Contains(string(body), `"timeType":2,"dateType":0,"date":"03/09/2026","localTime":"07:30","useTraffic":true`) {
+		t.Errorf("Expected local departure time in %s", body)
+	}
+}
+
+func TestTimeValidate(t *testing.T) {
+	directions := NewDirections("Amsterdam,Netherlands", []string{"Antwerp,Belgium"})
+	directions.TimeType = TimeArrival
+	if err := directions.Validate(); err == nil || !strings.Contains(err.Error(), "needs an ArrivalTime") {
+		t.Errorf("Expected missing ArrivalTime ~ Received %v", err)
+	}
+	directions.ArrivalTime = time.Now()
+	directions.DepartureTime = time.Now()
+	if err := directions.Validate(); err == nil || !strings.Contains(err.Error(), "exclusive") {
+		t.Errorf("Expected exclusive times ~ Received %v", err)
+	}
+}
+
+func TestETA(t *testing.T) {
+	departure := time.Date(2026, 3, 9, 7, 30, 0, 0, time.UTC)
+	route := Route{Time: 3600, RealTime: -1}
+	if eta := route.ETA(departure); !eta.Equal(departure.Add(time.Hour)) {
+		t.Errorf("Expected %v ~ Received %v", departure.Add(time.Hour), eta)
+	}
+	route.RealTime = 4500
+	if eta := route.ETA(departure); !eta.Equal(departure.Add(75 * time.Minute)) {
+		t.Errorf("Expected %v ~ Received %v", departure.Add(75*time.Minute), eta)
+	}
+	if latest := route.Departure(departure); !latest.Equal(departure.Add(-75 * time.Minute)) {
+		t.Errorf("Expected %v ~ Received %v", departure.Add(-75*time.Minute), latest)
+	}
+}
@@ -0,0 +1,179 @@
+/* Tracks the progress of a driver along a planned route.
+
+GPS fixes are snapped onto the route shape, so the route has to be requested
+with its full shape (FullShape). Distances of the progress are in the unit of
+the route, the corridor and offsets in meters.
+
+Example:
+
+directions.FullShape = true
+results, err := directions.Get()
+tracker, err := NewTracker(results.Route)
+progress := tracker.Update(LatLng{Lat: 51.52891, Lng: -0.27})
+if progress.OffRoute {
+	// plan a new route
+}
+
+*/
+
+package geocoder
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrNoShape is returned when a route has no (full) shape to track
+var ErrNoShape = errors.New("Route has no shape with maneuver indexes, request it with FullShape")
+
+// Tracker follows the progress along a route. A Tracker is not safe
+// for concurrent use.
+type Tracker struct {
+	// Route being followed
+	Route Route
+	// Distance in meters from the shape beyond which a fix is off route (default 50)
+	Corridor float64
+	// Renders the instructions if set, otherwise the route narratives are used
+	Renderer *NarrativeRenderer
+	// distance along the shape (meters) at each shape point
+	cumulative []float64
+	// all maneuvers of all legs
+	maneuvers []trackedManeuver
+	// segment of the last fix
+	segment int
+}
+
+// trackedManeuver locates a maneuver along the shape
+type trackedManeuver struct {
+	leg, index int
+	// distance along the shape (meters) at which the maneuver starts
+	start float64
+}
+
+// Progress along a route
+type Progress struct {
+	// Fix snapped onto the route
+	Position LatLng
+	// Distance in meters of the fix from the route
+	Offset float64
+	// Whether the fix lies outside the corridor
+	OffRoute bool
+	// Index of the current leg and of the current maneuver within the leg
+	Leg, Maneuver int
+	// Distance to the next maneuver
+	DistanceToNext float64
+	// Distance and time (seconds) to the destination
+	DistanceRemaining float64
+	TimeRemaining     int
+	// Next maneuver (nil at the last maneuver) and its instruction
+	Next        *Maneuver
+	Instruction string
+}
+
+// NewTracker is a constructor to initialize a Tracker for a route with a full shape.
+func NewTracker(route Route) (*Tracker, error) {
+	points := route.Shape.Points
+	indexes := route.Shape.ManeuverIndexes
+	if len(points) < 2 || len(indexes) == 0 {
+		return nil, ErrNoShape
+	}
+	tracker := &Tracker{Route: route, Corridor: 50, cumulative: make([]float64, len(points))}
+	for i := 1; i < len(points); i++ {
+		tracker.cumulative[i] = tracker.cumulative[i-1] + haversine(points[i-1], points[i])
+	}
+	k := 0
+	for l, leg := range route.Legs {
+		for m := range leg.Maneuvers {
+			index := len(points) - 1
+			if k < len(indexes) && indexes[k] < index {
+				index = indexes[k]
+			}
+			tracker.maneuvers = append(tracker.maneuvers, trackedManeuver{leg: l, index: m, start: tracker.cumulative[index]})
+			k++
+		}
+	}
+	if len(tracker.maneuvers) == 0 {
+		return nil, ErrNoShape
+	}
+	return tracker, nil
+}
+
+// project returns the point of the segment closest to point, its distance
+// (meters) from start along the segment and from point.
+func project(point, start, end LatLng) (LatLng, float64, float64) {
+	if start == end {
+		return start, 0, haversine(start, point)
+	}
+	_, along := crossTrack(point, start, end)
+	length := angularDistance(start, end)
+	along = math.Max(0, math.Min(along, length)) * earthRadius
+	projected := destination(start, start.Bearing(end), along)
+	return projected, along, haversine(projected, point)
+}
+
+// snap projects the fix onto the shape. Segments from the last fix onwards
+// are preferred, so routes passing the same road twice are followed in order.
+func (tracker *Tracker) snap(fix LatLng) (position LatLng, along, offset float64) {
+	points := tracker.Route.Shape.Points
+	best := func(from int) (int, LatLng, float64, float64) {
+		segment, offset := -1, math.Inf(1)
+		var position LatLng
+		var along float64
+		for i := from; i < len(points)-1; i++ {
+			projected, a, distance := project(fix, points[i], points[i+1])
+			if distance < offset {
+				segment, position, along, offset = i, projected, a, distance
+			}
+		}
+		return segment, position, along, offset
+	}
+	segment, position, along, offset := best(tracker.segment)
+	if offset > tracker.Corridor {
+		segment, position, along, offset = best(0)
+	}
+	tracker.segment = segment
+	return position, tracker.cumulative[segment] + along, offset
+}
+
+// Update snaps a fix onto the route and reports the progress.
+func (tracker *Tracker) Update(fix LatLng) Progress {
+	position, along, offset := tracker.snap(fix)
+	progress := Progress{Position: position, Offset: offset, OffRoute: offset > tracker.Corridor}
+	// the current maneuver is the last one started (within a millimeter)
+	k := 0
+	for k+1 < len(tracker.maneuvers) && tracker.maneuvers[k+1].start <= along+0.001 {
+		k++
+	}
+	current := tracker.maneuvers[k]
+	maneuver := tracker.Route.Legs[current.leg].Maneuvers[current.index]
+	progress.Leg, progress.Maneuver = current.leg, current.index
+	end := tracker.cumulative[len(tracker.cumulative)-1]
+	if k+1 < len(tracker.maneuvers) {
+		end = tracker.maneuvers[k+1].start
+	}
+	// part of the current maneuver still ahead
+	remaining := 0.0
+	if end > current.start {
+		remaining = math.Max(0, math.Min(1, (end-along)/(end-current.start)))
+	}
+	progress.DistanceToNext = remaining * maneuver.Distance
+	progress.DistanceRemaining = progress.DistanceToNext
+	timeRemaining := remaining * float64(maneuver.Time)
+	for _, later := range tracker.maneuvers[k+1:] {
+		next := tracker.Route.Legs[later.leg].Maneuvers[later.index]
+		progress.DistanceRemaining += next.Distance
+		timeRemaining += float64(next.Time)
+	}
+	progress.TimeRemaining = int(math.Round(timeRemaining))
+	if k+1 < len(tracker.maneuvers) {
+		next := tracker.maneuvers[k+1]
+		progress.Next = &tracker.Route.Legs[next.leg].Maneuvers[next.index]
+		progress.Instruction = progress.Next.Narrative
+		if tracker.Renderer != nil {
+			if instruction, err := tracker.Renderer.Maneuver(*progress.Next); err == nil {
+				progress.Instruction = instruction
+			}
+		}
+	}
+	return progress
+}
@@ -0,0 +1,62 @@
+package geocoder
+
+import (
+	"math"
+	"testing"
+)
+
+// trackedRoute is a route of two legs of 2 km eastwards along the equator
+func trackedRoute() Route {
+	var route Route
+	for i := 0; i <= 4; i++ {
+		route.Shape.Points = append(route.Shape.Points, destination(LatLng{}, 90, float64(i)*1000))
+	}
+	route.Shape.ManeuverIndexes = []int{0, 2, 2, 4}
+	for l := 0; l < 2; l++ {
+		route.Legs = append(route.Legs, Leg{Maneuvers: []Maneuver{
+			{Distance: 2, Time: 120, Narrative: "Go east.", TurnType: TurnStraight},
+			{Narrative: "Arrive.", TurnType: TurnNone},
+		}})
+	}
+	return route
+}
+
+func TestTracker(t *testing.T) {
+	tracker, err := NewTracker(trackedRoute())
+	if unexpected(err, t) {
+		return
+	}
+	progress := tracker.Update(destination(destination(LatLng{}, 90, 500), 0, 10))
+	if math.Abs(progress.Offset-10) > 1e-6 || progress.OffRoute || progress.Leg != 0 || progress.Maneuver != 0 {
+		t.Errorf("Unexpected progress %+v", progress)
+	}
+	if math.Abs(progress.DistanceToNext-1.5) > 1e-6 || math.Abs(progress.DistanceRemaining-3.5) > 1e-6 || progress.TimeRemaining != 210 {
+		t.Errorf("Expected 1.5, 3.5 and 210 ~ Received %f, %f and %d", progress.DistanceToNext, progress.DistanceRemaining, progress.TimeRemaining)
+	}
+	if progress.Next == nil || progress.Instruction != "Arrive." {
+		t.Errorf("Expected the next instruction Arrive. ~ Received %s", progress.Instruction)
+	}
+	progress = tracker.Update(destination(destination(LatLng{}, 90, 3000), 0, 100))
+	if !progress.OffRoute || progress.Leg != 1 || progress.Maneuver != 0 || math.Abs(progress.DistanceRemaining-1) > 1e-6 {
+		t.Errorf("Unexpected progress %+v", progress)
+	}
+	tracker.Renderer, _ = NewNarrativeRenderer("en", Kilometers)
+	progress = tracker.Update(destination(LatLng{}, 90, 4000))
+	if progress.Next != nil || progress.DistanceRemaining != 0 || progress.TimeRemaining != 0 || progress.Leg != 1 || progress.Maneuver != 1 {
+		t.Errorf("Unexpected progress at the destination %+v", progress)
+	}
+	if _, err = NewTracker(Route{}); err != ErrNoShape {
+		t.Errorf("Expected %v ~ Received %v", ErrNoShape, err)
+	}
+}
+
+func TestTrackerRendered(t *testing.T) {
+	tracker, err := NewTracker(trackedRoute())
+	if unexpected(err, t) {
+		return
+	}
+	tracker.Renderer, _ = NewNarrativeRenderer("nl", Kilometers)
+	if progress := tracker.Update(LatLng{}); progress.Instruction != "U bent op uw bestemming aangekomen." {
+		t.Errorf("Expected a Dutch instruction ~ Received %s", progress.Instruction)
+	}
+}
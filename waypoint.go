@@ -0,0 +1,138 @@
+package geocoder
+
+import "fmt"
+
+// StopType tells whether a route stops at a waypoint or passes through it
+type StopType string
+
+// Stop types
+const (
+	Stop StopType = "s"
+	Via  StopType = "v"
+)
+
+// SideOfStreet of a waypoint
+type SideOfStreet string
+
+// Sides of the street
+const (
+	SideLeft  SideOfStreet = "L"
+	SideRight SideOfStreet = "R"
+	SideNone  SideOfStreet = "N"
+)
+
+// Waypoint is a location of a route given as an address, a structured
+// address or a LatLng, together with options for the stop.
+type Waypoint struct {
+	// Single line address, eg "Antwerp,Belgium"
+	Address string
+	// Structured address (used if Address is empty), LatLng may be set as well
+	Location *Location
+	// Coordinates (used if Address and Location are empty)
+	LatLng *LatLng
+	// s (stop, default) or v (via point, passed without stopping)
+	Type StopType
+	// L(eft), R(ight) or N(one)
+	SideOfStreet SideOfStreet
+	// Whether the waypoint was dragged to its position on a map
+	DragPoint bool
+}
+
+// NewAddressWaypoint creates a waypoint from a single line address
+func NewAddressWaypoint(address string) Waypoint {
+	return Waypoint{Address: address}
+}
+
+// NewLocationWaypoint creates a waypoint from a structured address
+func NewLocationWaypoint(location Location) Waypoint {
+	return Waypoint{Location: &location}
+}
+
+// NewLatLngWaypoint creates a waypoint from coordinates
+func NewLatLngWaypoint(latLng LatLng) Waypoint {
+	return Waypoint{LatLng: &latLng}
+}
+
+// AddressWaypoints creates waypoints from single line addresses
+func AddressWaypoints(addresses []string) []Waypoint {
+	waypoints := make([]Waypoint, len(addresses))
+	for i, address := range addresses {
+		waypoints[i] = NewAddressWaypoint(address)
+	}
+	return waypoints
+}
+
+// IsZero reports whether no location is given
+func (waypoint Waypoint) IsZero() bool {
+	return waypoint.Address == "" && waypoint.Location == nil && waypoint.LatLng == nil
+}
+
+// hasOptions reports whether stop options are set
+func (waypoint Waypoint) hasOptions() bool {
+	return waypoint.Type != "" || waypoint.SideOfStreet != "" || waypoint.DragPoint
+}
+
+// String formats the waypoint as a single line address, or as a normalized "lat,lng".
+// The stop options are not included.
+func (waypoint Waypoint) String() string {
+	switch {
+	case waypoint.Address != "":
+		return waypoint.Address
+	case waypoint.Location != nil:
+		return waypoint.Location.singleLine()
+	case waypoint.LatLng != nil:
+		return waypoint.LatLng.Normalize().String()
+	}
+	return ""
+}
+
+// body converts the waypoint for use in a request body: a plain
+// single line address if possible, otherwise a location object.
+func (waypoint Waypoint) body() interface{} {
+	if waypoint.Address != "" && !waypoint.hasOptions() {
+		return waypoint.Address
+	}
+	var body locationBody
+	switch {
+	case waypoint.Address != "":
+		// the street field accepts a single line address
+		body.Street = waypoint.Address
+	case waypoint.Location != nil:
+		body = waypoint.Location.body()
+	case waypoint.LatLng != nil:
+		latLng := waypoint.LatLng.Normalize()
+		body.LatLng = &latLng
+	}
+	if waypoint.Type != "" {
+		body.Type = string(waypoint.Type)
+	}
+	if waypoint.SideOfStreet != "" {
+		body.SideOfStreet = string(waypoint.SideOfStreet)
+	}
+	body.DragPoint = body.DragPoint || waypoint.DragPoint
+	return body
+}
+
+// validate adds the errors of an invalid waypoint
+func (waypoint Waypoint) validate(v *validator, name string) {
+	v.check(!waypoint.IsZero(), "%s is empty", name)
+	if waypoint.Location != nil && waypoint.Location.LatLng != (LatLng{}) {
+		waypoint.Location.LatLng.Normalize().validate(v, name+".Location.LatLng")
+	}
+	if waypoint.LatLng != nil {
+		waypoint.LatLng.Normalize().validate(v, name+".LatLng")
+	}
+	if waypoint.Type != "" {
+		v.oneOf(name+".Type", string(waypoint.Type), string(Stop), string(Via))
+	}
+	if waypoint.SideOfStreet != "" {
+		v.oneOf(name+".SideOfStreet", string(waypoint.SideOfStreet), string(SideLeft), string(SideRight), string(SideNone))
+	}
+}
+
+// validateWaypoints adds the errors of all invalid waypoints
+func validateWaypoints(v *validator, name string, waypoints []Waypoint) {
+	for i, waypoint := range waypoints {
+		waypoint.validate(v, fmt.Sprintf("%s[%d]", name, i))
+	}
+}
@@ -0,0 +1,66 @@
+package geocoder
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestWaypointBody(t *testing.T) {
+	stop := NewAddressWaypoint("Antwerp,Belgium")
+	stop.SideOfStreet = SideRight
+	for _, test := range []struct {
+		waypoint Waypoint
+		expected string
+	}{
+		{NewAddressWaypoint("Antwerp,Belgium"), `"Antwerp,Belgium"`},
+		{stop, `{"street":"Antwerp,Belgium","sideOfStreet":"R"}`},
+		{NewLatLngWaypoint(LatLng{Lat: 51.22111, Lng: 4.399708}), `{"latLng":{"lat":51.22111,"lng":4.399708}}`},
+		{NewLocationWaypoint(Location{City: "Seattle", State: "WA", DragPoint: true}), `{"adminArea5":"Seattle","adminArea3":"WA","dragPoint":true}`},
+	} {
+		body, err := json.Marshal(test.waypoint.body())
+		if unexpected(err, t) {
+			continue
+		}
+		if string(body) != test.expected {
+			t.Errorf("Expected %s ~ Received %s", test.expected, body)
+		}
+	}
+}
+
+func TestWaypointValidate(t *testing.T) {
+	bad := NewAddressWaypoint("Antwerp,Belgium")
+	bad.Type = "stop"
+	directions := NewWaypointDirections(Waypoint{}, []Waypoint{bad})
+	err := directions.Validate()
+	if err == nil {
+		t.Fatalf("Expected validation error ~ Received nil")
+	}
+	for _, expected := range []string{"From is empty", `To[0].Type "stop"`} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected %s in %s", expected, err.Error())
+		}
+	}
+}
+
+func TestWaypointNormalize(t *testing.T) {
+	directions := NewWaypointDirections(
+		NewLatLngWaypoint(LatLng{Lat: 51, Lng: 364.5}),
+		[]Waypoint{NewLocationWaypoint(Location{LatLng: LatLng{Lat: 52, Lng: -355.25}})},
+	)
+	if err := directions.Validate(); unexpected(err, t) {
+		return
+	}
+	routeURL := directions.URL("json")
+	if !strings.Contains(routeURL, "&from=51%2C4.5&to=52%2C4.75&") {
+		t.Errorf("Expected normalized longitudes in %s", routeURL)
+	}
+	body, err := json.Marshal(directions.body())
+	if unexpected(err, t) {
+		return
+	}
+	expected := `"locations":[{"latLng":{"lat":51,"lng":4.5}},{"latLng":{"lat":52,"lng":4.75}}]`
+	if !strings.Contains(string(body), expected) {
+		t.Errorf("Expected body to contain %s ~ Received %s", expected, body)
+	}
+}
@@ -0,0 +1,252 @@
+/* Decodes mapquest xml responses (outFormat=xml) into the same types as
+the json responses.
+
+The xml is converted into json guided by the fields (and json tags) of the
+target type, so the custom json decoding of shapes and enums applies as well.
+Arrays are expected to be wrapped, eg <legs><leg>...</leg></legs>.
+
+Example:
+
+data, err := directions.Dump("xml")
+results, err := directions.DecodeXML(data)
+
+*/
+
+package geocoder
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// xmlNode is a generic xml element
+type xmlNode struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr `xml:",any,attr"`
+	Text     string     `xml:",chardata"`
+	Children []xmlNode  `xml:",any"`
+}
+
+// elements returns the child elements, attributes included as leaf elements
+func (node xmlNode) elements() []xmlNode {
+	elements := make([]xmlNode, 0, len(node.Attrs)+len(node.Children))
+	for _, attr := range node.Attrs {
+		elements = append(elements, xmlNode{XMLName: attr.Name, Text: attr.Value})
+	}
+	return append(elements, node.Children...)
+}
+
+// leaves calls fn with the text of all leaf elements in document order
+func (node xmlNode) leaves(fn func(text string)) {
+	elements := node.elements()
+	if len(elements) == 0 {
+		fn(strings.TrimSpace(node.Text))
+		return
+	}
+	for _, element := range elements {
+		element.leaves(fn)
+	}
+}
+
+var (
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+	numberType     = reflect.TypeOf(json.Number(""))
+	// types which are decoded from json through another (wire) type
+	xmlWireTypes = map[reflect.Type]reflect.Type{
+		reflect.TypeOf(Shape{}): reflect.TypeOf(shapeJSON{}),
+	}
+)
+
+// xmlField is a struct field by its json name
+type xmlField struct {
+	name string
+	typ  reflect.Type
+}
+
+// xmlFields returns the json fields of a struct type by lower case name
+func xmlFields(t reflect.Type, fields map[string]xmlField) map[string]xmlField {
+	if fields == nil {
+		fields = map[string]xmlField{}
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || (field.PkgPath != "" && !field.Anonymous) {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			xmlFields(field.Type, fields)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[strings.ToLower(name)] = xmlField{name: name, typ: field.Type}
+	}
+	return fields
+}
+
+// xmlLiteral returns numbers and booleans as json literals, other text as a string
+func xmlLiteral(text string) interface{} {
+	if text == "true" || text == "false" {
+		return json.RawMessage(text)
+	}
+	if _, err := strconv.ParseFloat(text, 64); err == nil && json.Valid([]byte(text)) {
+		return json.RawMessage(text)
+	}
+	return text
+}
+
+// xmlItems converts the elements of a (wrapped) array
+func xmlItems(node xmlNode, t reflect.Type) []interface{} {
+	elements := node.elements()
+	if len(elements) == 0 {
+		if text := strings.TrimSpace(node.Text); text != "" {
+			return []interface{}{xmlValue(node, t)}
+		}
+		return []interface{}{}
+	}
+	items := make([]interface{}, 0, len(elements))
+	for _, element := range elements {
+		if item := xmlValue(element, t); item != nil {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// xmlValue converts an xml element into a json value for type t.
+// Empty leaf elements of non string types are left out (nil).
+func xmlValue(node xmlNode, t reflect.Type) interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if wire, ok := xmlWireTypes[t]; ok {
+		t = wire
+	}
+	text := strings.TrimSpace(node.Text)
+	elements := node.elements()
+	switch t {
+	case rawMessageType:
+		// text (eg compressed shape points) or all leaf values as an array
+		if len(elements) == 0 {
+			return xmlLiteral(text)
+		}
+		values := []interface{}{}
+		node.leaves(func(text string) {
+			values = append(values, xmlLiteral(text))
+		})
+		return values
+	case numberType:
+		if text == "" {
+			return nil
+		}
+		return xmlLiteral(text)
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		fields := xmlFields(t, nil)
+		object := map[string]interface{}{}
+		for _, element := range elements {
+			// attributes of leaves fill their sibling fields,
+			// eg <adminArea5 type="City"> fills adminArea5Type
+			for _, attr := range element.Attrs {
+				field, ok := fields[strings.ToLower(element.XMLName.Local+attr.Name.Local)]
+				if !ok {
+					continue
+				}
+				if value := xmlValue(xmlNode{XMLName: attr.Name, Text: attr.Value}, field.typ); value != nil {
+					object[field.name] = value
+				}
+			}
+			field, ok := fields[strings.ToLower(element.XMLName.Local)]
+			if !ok {
+				continue
+			}
+			if field.typ.Kind() == reflect.Slice && field.typ != rawMessageType {
+				items := xmlItems(element, field.typ.Elem())
+				if previous, ok := object[field.name].([]interface{}); ok {
+					items = append(previous, items...)
+				}
+				object[field.name] = items
+			} else if value := xmlValue(element, field.typ); value != nil {
+				object[field.name] = value
+			}
+		}
+		return object
+	case reflect.Slice, reflect.Array:
+		return xmlItems(node, t.Elem())
+	case reflect.Map, reflect.Interface:
+		if len(elements) == 0 {
+			return xmlLiteral(text)
+		}
+		elem := t
+		if t.Kind() == reflect.Map {
+			elem = t.Elem()
+		}
+		object := map[string]interface{}{}
+		for _, element := range elements {
+			if value := xmlValue(element, elem); value != nil {
+				object[element.XMLName.Local] = value
+			}
+		}
+		return object
+	case reflect.String:
+		return text
+	}
+	if text == "" {
+		return nil
+	}
+	return xmlLiteral(text)
+}
+
+// DecodeXML decodes a mapquest xml response into v, a pointer to the type
+// of the json response (eg DirectionsResults or GeocodingResult).
+func DecodeXML(r io.Reader, v interface{}) error {
+	t := reflect.TypeOf(v)
+	if t == nil || t.Kind() != reflect.Ptr {
+		return fmt.Errorf("Error decoding xml: %T is not a pointer", v)
+	}
+	var root xmlNode
+	if err := xml.NewDecoder(r).Decode(&root); err != nil {
+		return fmt.Errorf("Error decoding xml: <%v>", err)
+	}
+	data, err := json.Marshal(xmlValue(root, t.Elem()))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// DecodeXML decodes the xml directions (see Dump) of the directions.
+// The shape points are decoded with the ShapeFormat of the directions.
+func (directions Directions) DecodeXML(data []byte) (*DirectionsResults, error) {
+	results := &DirectionsResults{}
+	if err := DecodeXML(bytes.NewReader(data), results); err != nil {
+		return nil, err
+	}
+	if err := directions.complete(results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// DecodeGeocodingXML decodes a xml geocoding, reverse geocoding or batch
+// geocoding response.
+func DecodeGeocodingXML(data []byte) (*GeocodingResult, error) {
+	result := &GeocodingResult{}
+	if err := DecodeXML(bytes.NewReader(data), result); err != nil {
+		return nil, err
+	}
+	if result.Info.Statuscode != 0 {
+		return nil, result.Info
+	}
+	return result, nil
+}
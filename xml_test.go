@@ -0,0 +1,104 @@
+package geocoder
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestDirectionsXML(t *testing.T) {
+	fixture, err := ioutil.ReadFile("testdata/route.json")
+	if unexpected(err, t) {
+		return
+	}
+	var expected DirectionsResults
+	if err = json.Unmarshal(fixture, &expected); unexpected(err, t) {
+		return
+	}
+	fixture, err = ioutil.ReadFile("testdata/route.xml")
+	if unexpected(err, t) {
+		return
+	}
+	received, err := NewDirections("", nil).DecodeXML(fixture)
+	if unexpected(err, t) {
+		return
+	}
+	if !reflect.DeepEqual(received.Route, expected.Route) {
+		t.Errorf("Expected %+v ~ Received %+v", expected.Route, received.Route)
+	}
+	// <adminArea5 type="City">London</adminArea5>
+	if location := received.Route.Locations[0]; location.City != "London" || location.CityType != "City" || location.CountyType != "County" {
+		t.Errorf("Expected London City ~ Received %s %s", location.City, location.CityType)
+	}
+	if received.Info.Statuscode != 0 || received.Info.Copyright.Text != expected.Info.Copyright.Text {
+		t.Errorf("Expected %+v ~ Received %+v", expected.Info, received.Info)
+	}
+}
+
+func TestDirectionsXMLCompressed(t *testing.T) {
+	directions := NewDirections("", nil)
+	directions.ShapeFormat = ShapeCmp
+	points := []LatLng{{Lat: 38.5, Lng: -120.2}, {Lat: 40.7, Lng: -120.95}}
+	data := []byte(`<response><route><shape><shapePoints>` + EncodeShape(points, 5) +
+		`</shapePoints></shape><legs><leg><maneuvers><maneuver><turnType>6</turnType><attributes>1</attributes>` +
+		`<streets><street>Main St</street></streets></maneuver></maneuvers></leg></legs></route>` +
+		`<info><statusCode>0</statusCode></info></response>`)
+	results, err := directions.DecodeXML(data)
+	if unexpected(err, t) {
+		return
+	}
+	if !reflect.DeepEqual(results.Route.Shape.Points, points) {
+		t.Errorf("Expected %v ~ Received %v", points, results.Route.Shape.Points)
+	}
+	maneuver := results.Route.Legs[0].Maneuvers[0]
+	if maneuver.TurnType != TurnLeft || !maneuver.Attributes.Has(AttributeToll) || maneuver.Streets[0] != "Main St" {
+		t.Errorf("Unexpected maneuver %+v", maneuver)
+	}
+	data = []byte(`<response><info><statusCode>400</statusCode><messages><message>Illegal argument</message></messages></info></response>`)
+	if _, err = directions.DecodeXML(data); err == nil || err.Error() != (Info{Statuscode: 400, Messages: []string{"Illegal argument"}}).Error() {
+		t.Errorf("Expected the status error ~ Received %v", err)
+	}
+}
+
+func TestGeocodingXML(t *testing.T) {
+	data := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<response>
+  <info><statusCode>0</statusCode><messages/></info>
+  <options><maxResults>1</maxResults><thumbMaps>false</thumbMaps></options>
+  <results>
+    <result>
+      <providedLocation><location>Seattle WA</location></providedLocation>
+      <locations>
+        <location>
+          <adminArea5 type="City">Seattle</adminArea5>
+          <adminArea3Type>State</adminArea3Type>
+          <adminArea3>WA</adminArea3>
+          <geocodeQuality>CITY</geocodeQuality>
+          <latLng><lat>47.603561</lat><lng>-122.329437</lng></latLng>
+        </location>
+      </locations>
+    </result>
+    <result>
+      <providedLocation><location>Nowhere</location></providedLocation>
+      <locations/>
+    </result>
+  </results>
+</response>`)
+	result, err := DecodeGeocodingXML(data)
+	if unexpected(err, t) {
+		return
+	}
+	if result.Options.MaxResults != 1 || len(result.Results) != 2 || result.Results[0].ProvidedLocation.Location != "Seattle WA" {
+		t.Errorf("Unexpected result %+v", result)
+		return
+	}
+	location := result.Results[0].Locations[0]
+	if location.AdminArea5 != "Seattle" || location.AdminArea5Type != "City" || location.AdminArea3 != "WA" || location.GeocodeQuality != "CITY" {
+		t.Errorf("Unexpected location %+v", location)
+	}
+	expected := []LatLng{{Lat: 47.603561, Lng: -122.329437}, {}}
+	if latLngs := result.LatLngs(); !reflect.DeepEqual(latLngs, expected) {
+		t.Errorf("Expected %v ~ Received %v", expected, latLngs)
+	}
+}